@@ -7,7 +7,8 @@ import (
 )
 
 type Callable interface {
-	arity() int
+	parameters() []parameter
+	// Receives one argument per parameter, as produced by bindArguments.
 	call(interpreter *Interpreter, args []any) (any, error)
 }
 
@@ -25,18 +26,48 @@ type Function struct { // implements Callable
 	declaration spec.FuncStmt
 	closure *environment
 	isInit bool
+	// The class whose body declares the function, directly or nested in a method; nil outside of classes
+	class *Class
 }
-func (f Function) arity() int {
-	return len(f.declaration.Params)
+func (f *Function) parameters() []parameter {
+	params := []parameter{}
+	for _, param := range f.declaration.Params {
+		params = append(params, parameter{name: param.Name.Lexeme, optional: param.Default != nil, rest: param.IsRest})
+	}
+	return params
+}
+func (f *Function) call(interpreter *Interpreter, args []any) (any, error) {
+	// Calls in tail position come back as a tailCall instead of recursing, and are performed here in a loop, so that
+	// tail recursion runs in constant Go stack space.
+	for {
+		value, callError := f.callBody(interpreter, args)
+		next, isTailCall := callError.(tailCall)
+		if !isTailCall {
+			return value, callError
+		}
+		interpreter.traceCall(next.function, next.args, next.line, true)
+		f, args = next.function, next.args
+	}
 }
-func (f Function) call(interpreter *Interpreter, args []any) (any, error) {
-	origEnv := interpreter.env
+func (f *Function) callBody(interpreter *Interpreter, args []any) (any, error) {
+	origEnv, origClass := interpreter.env, interpreter.currentClass
 	subenv := newEnvWithParent(f.closure)
-	interpreter.env = &subenv
-	defer func(){ interpreter.env = origEnv }();
+	interpreter.env, interpreter.currentClass = &subenv, f.class
+	defer func(){ interpreter.env, interpreter.currentClass = origEnv, origClass }();
 
 	for i, param := range f.declaration.Params {
-		interpreter.env.define(param.Lexeme, args[i])
+		value := args[i]
+		if _, isMissing := value.(missingArgument); isMissing {
+			defaultValue, defaultError := param.Default.Eval(interpreter)
+			if defaultError != nil {
+				return nil, defaultError
+			}
+			value = defaultValue
+		}
+		interpreter.env.define(param.Name.Lexeme, value)
+	}
+	if f.declaration.IsGenerator {
+		return newGenerator(f, interpreter, &subenv), nil
 	}
 
 	execError := interpreter.ExecBlock(&f.declaration.Body, &subenv)
@@ -54,12 +85,22 @@ func (f Function) call(interpreter *Interpreter, args []any) (any, error) {
 	}
 	return nil, nil
 }
-func (f Function) bind(inst ClassInstance) Function {
+func (f *Function) bind(inst *ClassInstance) *Function {
 	closure := newEnvWithParent(f.closure)
 	closure.define("this", inst)
-	return Function{declaration: f.declaration, closure: &closure, isInit: f.isInit}
+	return &Function{declaration: f.declaration, closure: &closure, isInit: f.isInit, class: f.class}
 }
-func (f Function) String() string {
+// Binds a method to an instance as the value of a property: a getter is called, and any other method is returned.
+func (f *Function) bindProperty(intp *Interpreter, inst *ClassInstance) (any, error) {
+	if f.declaration.IsGetter {
+		return intp.callWith(f.bind(inst))
+	}
+	return f.bind(inst), nil
+}
+func (f *Function) String() string {
+	if f.declaration.Name.Type != spec.Identifier {
+		return "<fn anonymous>"
+	}
 	return fmt.Sprintf("<fn %v>", f.declaration.Name.Lexeme)
 }
 
@@ -67,19 +108,111 @@ func (f Function) String() string {
 
 type NativeFunction struct {
 	_name string
-	_arity int
-	_func func(args []any) any
+	_params []parameter
+	_func func(intp *Interpreter, args []any) (any, error)
 }
-func (nf NativeFunction) arity() int {
-	return nf._arity
+func (nf *NativeFunction) parameters() []parameter {
+	return nf._params
 }
-func (nf NativeFunction) call(interpreter *Interpreter, args []any) (any, error) {
-	return nf._func(args), nil
+func (nf *NativeFunction) call(interpreter *Interpreter, args []any) (any, error) {
+	for i, arg := range args {
+		if _, isMissing := arg.(missingArgument); isMissing {
+			args[i] = nil
+		}
+	}
+	return nf._func(interpreter, args)
 }
-func (nf NativeFunction) String() string {
+func (nf *NativeFunction) String() string {
 	return fmt.Sprintf("<nat fn %v>", nf._name)
 }
 
+// MARK: - Argument binding
+
+type parameter struct {
+	name string
+	optional bool
+	rest bool
+}
+
+type namedArgument struct {
+	name string
+	value any
+}
+
+// Stands in for an optional parameter that received no argument; the callee substitutes the default value.
+type missingArgument struct{}
+func (ma missingArgument) String() string {
+	return "<default>"
+}
+
+// Matches positional and named arguments to the callable's parameters, and returns one value per parameter. Extra
+// positional arguments are collected into a list if there is a rest parameter.
+func bindArguments(callable Callable, positional []any, named []namedArgument) ([]any, error) {
+	params := callable.parameters()
+	bound := make([]any, len(params))
+	isBound := make([]bool, len(params))
+
+	rest := []any{}
+	for i, arg := range positional {
+		if i < len(params) && !params[i].rest {
+			bound[i], isBound[i] = arg, true
+		} else if len(params) > 0 && params[len(params) - 1].rest {
+			rest = append(rest, arg)
+		} else if hasOnlyRequired(params) {
+			return nil, fmt.Errorf("expected %v arguments but got %v", len(params), len(positional))
+		} else {
+			return nil, fmt.Errorf("expected at most %v arguments but got %v", len(params), len(positional))
+		}
+	}
+
+	for _, arg := range named {
+		index := -1
+		for i, param := range params {
+			if param.name == arg.name && !param.rest {
+				index = i
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("unknown argument '%v'", arg.name)
+		} else if isBound[index] {
+			return nil, fmt.Errorf("duplicate argument '%v'", arg.name)
+		}
+		bound[index], isBound[index] = arg.value, true
+	}
+
+	for i, param := range params {
+		if param.rest {
+			bound[i] = newList(rest)
+		} else if !isBound[i] && param.optional {
+			bound[i] = missingArgument{}
+		} else if !isBound[i] {
+			if hasOnlyRequired(params) && len(named) == 0 {
+				return nil, fmt.Errorf("expected %v arguments but got %v", len(params), len(positional))
+			}
+			return nil, fmt.Errorf("missing argument '%v'", param.name)
+		}
+	}
+	return bound, nil
+}
+
+// Calls a callable from native code with positional arguments, e.g. a comparator passed to a built-in method.
+func (intp *Interpreter) callWith(callable Callable, args ...any) (any, error) {
+	boundArgs, bindError := bindArguments(callable, args, nil)
+	if bindError != nil {
+		return nil, bindError
+	}
+	return callable.call(intp, boundArgs)
+}
+
+func hasOnlyRequired(params []parameter) bool {
+	for _, param := range params {
+		if param.optional || param.rest {
+			return false
+		}
+	}
+	return true
+}
+
 // MARK: - Return "Error"
 
 type Return struct {
@@ -91,3 +224,17 @@ func (r Return) Error() string {
 		r.value,
 	)
 }
+
+// MARK: - Tail Call "Error"
+
+type tailCall struct {
+	function *Function
+	args []any
+	line uint64
+}
+func (tc tailCall) Error() string {
+	return fmt.Sprintf(
+		"error: this should not be an error! A tail call to %v was not performed by the enclosing function.",
+		tc.function,
+	)
+}
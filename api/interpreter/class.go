@@ -1,65 +1,320 @@
 package interpreter
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/spec"
 )
 
+// Classes, their instances and functions are always handled through a pointer, so that each one has an identity:
+// two references are equal only if they refer to the same object.
 type Class struct { // implements Callable
 	Name string
-	Methods map[string]Function
+	Methods map[string]*Function
 	Superclass *Class
+	// Methods and fields declared with `class`, which subclasses inherit
+	StaticMethods map[string]*Function
+	StaticFields map[string]any
+	// Methods declared with `set`, which are called instead of assigning to a field
+	Setters map[string]*Function
+	// Fields declared with `var`, whose initializers are evaluated for each new instance
+	Fields []spec.DeclareStmt
+	// The environment the class is declared in, where the field initializers are evaluated
+	env *environment
+	// Methods whose name starts with '#', which only this class's own methods can call, and which aren't inherited
+	PrivateMethods map[string]*Function
 }
-func (class Class) String() string {
+func (class *Class) String() string {
 	return class.Name
 }
-func (class Class) findMethod(name string) (Function, bool) {
+func (class *Class) findMethod(name string) (*Function, bool) {
 	function, contains := class.Methods[name]
 	if !contains && class.Superclass != nil {
 		return class.Superclass.findMethod(name)
 	}
 	return function, contains
 }
+func (class *Class) findSetter(name string) (*Function, bool) {
+	setter, contains := class.Setters[name]
+	if !contains && class.Superclass != nil {
+		return class.Superclass.findSetter(name)
+	}
+	return setter, contains
+}
+// Copies the methods of traits into the class. The class's own methods take precedence over trait methods, and trait
+// methods over inherited ones; a method that several traits define, but the class doesn't, is a conflict.
+func (class *Class) includeTraits(traits []*Trait, line uint64) error {
+	providers := make(map[string]*Trait)
+	for _, trait := range traits {
+		for _, name := range methodNames(trait.Methods) {
+			method := trait.Methods[name]
+			table := class.Methods
+			if isPrivate(name) {
+				table = class.PrivateMethods
+			}
+			provider, isFromTrait := providers[name]
+			if _, isDefined := table[name]; isDefined && !isFromTrait {
+				continue
+			} else if isFromTrait && provider != trait {
+				message := fmt.Sprintf("Method '%v' is defined by both %v and %v", name, provider.Name, trait.Name)
+				return runtimeError{message: message, line: line}
+			}
+			table[name] = &Function{declaration: method.declaration, closure: method.closure, isInit: method.isInit, class: class}
+			providers[name] = trait
+		}
+	}
+	return nil
+}
+// Returns the names of the methods in alphabetical order, so that errors about them are deterministic.
+func methodNames(methods map[string]*Function) []string {
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+// Initializes the declared fields of a new instance, those of superclasses first, with `this` bound to the instance.
+func (class *Class) initializeFields(intp *Interpreter, inst *ClassInstance) error {
+	if class.Superclass != nil {
+		if superError := class.Superclass.initializeFields(intp, inst); superError != nil {
+			return superError
+		}
+	}
+	if len(class.Fields) == 0 {
+		return nil
+	}
+	origEnv, origClass := intp.env, intp.currentClass
+	env := newEnvWithParent(class.env)
+	env.define("this", inst)
+	intp.env, intp.currentClass = &env, class
+	defer func() { intp.env, intp.currentClass = origEnv, origClass }()
+	for _, field := range class.Fields {
+		value, evalError := field.Expr.Eval(intp)
+		if evalError != nil {
+			return evalError
+		}
+		// a declared field is defined on the instance, without calling a setter of the same name
+		if isPrivate(field.Identifier.Lexeme) {
+			inst.setPrivate(intp, field.Identifier.Lexeme, value)
+		} else {
+			inst.Fields[field.Identifier.Lexeme] = value
+		}
+	}
+	return nil
+}
+
+// Checks whether the class is the other class, or inherits from it.
+func (class *Class) isSubclassOf(other *Class) bool {
+	for current := class; current != nil; current = current.Superclass {
+		if current == other {
+			return true
+		}
+	}
+	return false
+}
+// Looks up a static field or method, on this class or the closest superclass that has it.
+func (class *Class) get(name string) (any, error) {
+	for current := class; current != nil; current = current.Superclass {
+		if value, contains := current.StaticFields[name]; contains {
+			return value, nil
+		} else if method, contains := current.StaticMethods[name]; contains {
+			return method, nil
+		}
+	}
+	return nil, fmt.Errorf("undefined property %v", name)
+}
+// Sets a static field on this class, even if a superclass has a field with the same name.
+func (class *Class) set(name string, value any) {
+	class.StaticFields[name] = value
+}
 
 type ClassInstance struct {
 	Class *Class
 	Fields map[string]any
+	// Fields whose name starts with '#', kept apart for each class that declares them, so that a class and its
+	// subclasses can use the same private name without sharing the field
+	PrivateFields map[*Class]map[string]any
 }
-func (inst ClassInstance) String() string {
+func (inst *ClassInstance) String() string {
 	return inst.Class.Name + " instance"
 }
-func (inst ClassInstance) get(name string) (any, error) {
+// Looks up a field or method. A getter is called, and its result is the value of the property.
+func (inst *ClassInstance) get(intp *Interpreter, name string) (any, error) {
+	if isPrivate(name) {
+		return inst.getPrivate(intp, name)
+	}
 	if value, contains := inst.Fields[name]; contains {
 		return value, nil
 	} else if method, contains := inst.Class.findMethod(name); contains {
-		return method.bind(inst), nil
+		return method.bindProperty(intp, inst)
 	} else {
 		return nil, fmt.Errorf("undefined property %v", name)
 	}
 }
-func (inst ClassInstance) set(name string, value any) error {
+// Assigns to a property, calling its setter if the class or a superclass declares one.
+func (inst *ClassInstance) set(intp *Interpreter, name string, value any) error {
+	if isPrivate(name) {
+		return inst.setPrivate(intp, name, value)
+	}
+	if setter, contains := inst.Class.findSetter(name); contains {
+		_, callError := intp.callWith(setter.bind(inst), value)
+		return callError
+	}
 	inst.Fields[name] = value
 	return nil
 }
 
+// Looks up a private field or method of the class that's accessing it.
+func (inst *ClassInstance) getPrivate(intp *Interpreter, name string) (any, error) {
+	class := intp.currentClass
+	if class == nil {
+		return nil, errors.New("Private member accessed outside class")
+	}
+	if value, contains := inst.PrivateFields[class][name]; contains {
+		return value, nil
+	} else if method, contains := class.PrivateMethods[name]; contains {
+		return method.bindProperty(intp, inst)
+	}
+	return nil, fmt.Errorf("undefined property %v", name)
+}
+func (inst *ClassInstance) setPrivate(intp *Interpreter, name string, value any) error {
+	class := intp.currentClass
+	if class == nil {
+		return errors.New("Private member accessed outside class")
+	}
+	if inst.PrivateFields[class] == nil {
+		inst.PrivateFields[class] = make(map[string]any)
+	}
+	inst.PrivateFields[class][name] = value
+	return nil
+}
+
+func isPrivate(name string) bool {
+	return strings.HasPrefix(name, "#")
+}
+
+// A named set of methods that classes include with `with`. The methods are copied into each class that uses the trait,
+// so `this` is an instance of that class, and private members belong to it.
+type Trait struct {
+	Name string
+	Methods map[string]*Function
+}
+func (trait *Trait) String() string {
+	return fmt.Sprintf("<trait %v>", trait.Name)
+}
+
+// A named set of methods, with their parameters, that classes declaring `implements` must have.
+type Interface struct {
+	Name string
+	Methods []spec.FuncStmt
+}
+func (iface *Interface) String() string {
+	return fmt.Sprintf("<interface %v>", iface.Name)
+}
+
+// Checks that the class has, or inherits, every method of the interfaces, with the same number of parameters. An
+// abstract method counts, since the class can't be instantiated until a subclass implements it.
+func (class *Class) checkInterfaces(interfaces []*Interface, line uint64) error {
+	for _, iface := range interfaces {
+		for _, required := range iface.Methods {
+			method, found := class.findMethod(required.Name.Lexeme)
+			if !found {
+				message := fmt.Sprintf(
+					"Class %v doesn't implement method '%v' of interface %v", class.Name, required.Name.Lexeme, iface.Name,
+				)
+				return runtimeError{message: message, line: line}
+			}
+			requirement := "interface " + iface.Name
+			if arityError := checkArity(class, method.declaration, required, requirement, line); arityError != nil {
+				return arityError
+			}
+		}
+	}
+	return nil
+}
+
+// Checks that the class's own methods that implement an inherited abstract method have the same number of parameters.
+func (class *Class) checkAbstractOverrides(line uint64) error {
+	if class.Superclass == nil {
+		return nil
+	}
+	for _, name := range methodNames(class.Methods) {
+		method := class.Methods[name]
+		inherited, found := class.Superclass.findMethod(name)
+		if !found || !inherited.declaration.IsAbstract || inherited.class == nil {
+			continue
+		}
+		requirement := "abstract method of " + inherited.class.Name
+		if arityError := checkArity(class, method.declaration, inherited.declaration, requirement, line); arityError != nil {
+			return arityError
+		}
+	}
+	return nil
+}
+
+func checkArity(class *Class, method spec.FuncStmt, required spec.FuncStmt, requirement string, line uint64) error {
+	if len(method.Params) == len(required.Params) {
+		return nil
+	}
+	message := fmt.Sprintf(
+		"Method '%v' of class %v takes %v parameters, but %v requires %v",
+		method.Name.Lexeme, class.Name, len(method.Params), requirement, len(required.Params),
+	)
+	return runtimeError{message: message, line: line}
+}
+
+// Finds a method that the class declares or inherits as abstract, and doesn't implement. Returns the first one in
+// alphabetical order, so that errors are deterministic.
+func (class *Class) findAbstractMethod() (string, bool) {
+	names := []string{}
+	for current := class; current != nil; current = current.Superclass {
+		for name, method := range current.Methods {
+			if method.declaration.IsAbstract {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if method, _ := class.findMethod(name); method.declaration.IsAbstract {
+			return name, true
+		}
+	}
+	return "", false
+}
+
 type ClassType int
 const (
 	CtNone ClassType = iota
 	CtClass
 	CtSubclass
+	CtTrait
 )
 
 // MARK: - Class Callable
 
-func (class Class) arity() int {
+func (class *Class) parameters() []parameter {
 	if init, contains := class.findMethod("init"); contains {
-		return init.arity()
+		return init.parameters()
 	}
-	return 0
+	return []parameter{}
 }
-func (class Class) call(intp *Interpreter, args []any) (any, error) {
-	inst := ClassInstance{Class: &class, Fields: make(map[string]any)}
+func (class *Class) call(intp *Interpreter, args []any) (any, error) {
+	if name, isAbstract := class.findAbstractMethod(); isAbstract {
+		return nil, fmt.Errorf("Can't instantiate abstract class %v; method '%v' is not implemented", class.Name, name)
+	}
+	inst := &ClassInstance{Class: class, Fields: make(map[string]any), PrivateFields: make(map[*Class]map[string]any)}
+	if fieldError := class.initializeFields(intp, inst); fieldError != nil {
+		return nil, fieldError
+	}
 	if init, contains := inst.Class.findMethod("init"); contains {
-		init.bind(inst).call(intp, args)
+		if _, initError := init.bind(inst).call(intp, args); initError != nil {
+			return nil, initError
+		}
 	}
 	return inst, nil
 }
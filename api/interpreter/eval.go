@@ -2,7 +2,8 @@ package interpreter
 
 import (
 	"fmt"
-	"reflect"
+	"math/big"
+	"strings"
 
 	"github.com/codecrafters-io/interpreter-starter-go/spec"
 )
@@ -19,6 +20,13 @@ func (intp *Interpreter) VisitUnary(ue spec.UnaryExpr) (any, error) {
 	subvalue, suberror := ue.Expr.Eval(intp)
 	if suberror != nil { return nil, suberror }
 
+	if isInstance(subvalue) && ue.Opt.Type != spec.Bang {
+		if result, isOverloaded, err := intp.overloadUnary(ue.Opt, subvalue); isOverloaded {
+			return result, err
+		}
+		return nil, undefinedOperator(ue.Opt.Lexeme, ue.Opt.Line, subvalue)
+	}
+
 	switch ue.Opt.Type {
 	case spec.Bang:
 		return !isTruthy(subvalue), nil
@@ -26,7 +34,12 @@ func (intp *Interpreter) VisitUnary(ue spec.UnaryExpr) (any, error) {
 		if !isNumber(subvalue) {
 			return nil, runtimeError{message: "Operand must be a number", line: ue.Opt.Line}
 		}
-		return -subvalue.(float64), nil
+		return negateNumber(subvalue), nil
+	case spec.Tilde:
+		if inverted, ok := invertNumber(subvalue); ok {
+			return inverted, nil
+		}
+		return nil, runtimeError{message: "Operand must be an integer", line: ue.Opt.Line}
 	}
 	
 	message := fmt.Sprintf("Unexpected type of unary expression: %s", ue.Opt.Type.String())
@@ -39,54 +52,107 @@ func (intp *Interpreter) VisitBinary(be spec.BinaryExpr) (any, error) {
 	if leftError != nil { return nil, leftError }
 	if rightError != nil { return nil, rightError }
 
+	if be.Opt.Type == spec.Is {
+		class, isClass := rightValue.(*Class)
+		if !isClass {
+			return nil, runtimeError{message: "Right operand of 'is' must be a class", line: be.Opt.Line}
+		}
+		inst, ok := leftValue.(*ClassInstance)
+		return ok && inst.Class.isSubclassOf(class), nil
+	}
+
+	if isInstance(leftValue) || isInstance(rightValue) {
+		if result, isOverloaded, err := intp.overloadBinary(be.Opt, leftValue, rightValue); isOverloaded {
+			return result, err
+		}
+		// an instance that defines toString() can be concatenated with a string
+		if be.Opt.Type == spec.Plus && (isString(leftValue) || isString(rightValue)) && hasToString(leftValue, rightValue) {
+			return intp.concatenate(leftValue, rightValue, be.Opt.Line)
+		}
+		// without `__eq__`, instances are compared with equals(), or by identity
+		if be.Opt.Type != spec.EqualEqual && be.Opt.Type != spec.BangEqual {
+			return nil, undefinedOperator(be.Opt.Lexeme, be.Opt.Line, leftValue, rightValue)
+		}
+	}
+
 	switch be.Opt.Type {
 	case spec.Star:
 		if !isNumber(leftValue) || !isNumber(rightValue) {
 			return nil, runtimeError{message: operandsMustBeNumbers, line: be.Opt.Line}
 		}
-		return leftValue.(float64) * rightValue.(float64), nil
+		return multiplyNumbers(leftValue, rightValue), nil
 	case spec.Slash:
 		if !isNumber(leftValue) || !isNumber(rightValue) {
 			return nil, runtimeError{message: operandsMustBeNumbers, line: be.Opt.Line}
 		}
-		return leftValue.(float64) / rightValue.(float64), nil
+		return divideNumbers(leftValue, rightValue), nil
+	case spec.TildeSlash, spec.Percent:
+		if !isNumber(leftValue) || !isNumber(rightValue) {
+			return nil, runtimeError{message: operandsMustBeNumbers, line: be.Opt.Line}
+		}
+		if isZero(rightValue) {
+			return nil, runtimeError{message: "Division by zero", line: be.Opt.Line}
+		}
+		if be.Opt.Type == spec.TildeSlash {
+			return floorDivideNumbers(leftValue, rightValue), nil
+		}
+		return moduloNumbers(leftValue, rightValue), nil
+	case spec.StarStar:
+		if !isNumber(leftValue) || !isNumber(rightValue) {
+			return nil, runtimeError{message: operandsMustBeNumbers, line: be.Opt.Line}
+		}
+		result, err := powerNumbers(leftValue, rightValue)
+		if err != nil {
+			return nil, runtimeError{message: err.Error(), line: be.Opt.Line, cause: err}
+		}
+		return result, nil
+	case spec.Ampersand, spec.Pipe, spec.Caret, spec.LessLess, spec.GreaterGreater:
+		result, err := bitwiseNumbers(be.Opt.Type, leftValue, rightValue)
+		if err != nil {
+			return nil, runtimeError{message: err.Error(), line: be.Opt.Line, cause: err}
+		}
+		return result, nil
 	case spec.Minus:
 		if !isNumber(leftValue) || !isNumber(rightValue) {
 			return nil, runtimeError{message: operandsMustBeNumbers, line: be.Opt.Line}
 		}
-		return leftValue.(float64) - rightValue.(float64), nil
+		return subtractNumbers(leftValue, rightValue), nil
 	case spec.Plus:
 		if isNumber(leftValue) && isNumber(rightValue) {
-			return leftValue.(float64) + rightValue.(float64), nil
+			return addNumbers(leftValue, rightValue), nil
 		}
 		if isString(leftValue) && isString(rightValue) {
 			return leftValue.(string) + rightValue.(string), nil
 		}
 		return nil, runtimeError{message: "Operands must be two numbers or two strings", line: be.Opt.Line}
-	case spec.Less:
-		if !isNumber(leftValue) || !isNumber(rightValue) {
+	case spec.Less, spec.LessEqual, spec.Greater, spec.GreaterEqual:
+		var comparison int
+		if isNumber(leftValue) && isNumber(rightValue) {
+			var comparable bool
+			if comparison, comparable = compareNumbers(leftValue, rightValue); !comparable {
+				return false, nil
+			}
+		} else if isString(leftValue) && isString(rightValue) {
+			comparison = strings.Compare(leftValue.(string), rightValue.(string))
+		} else {
 			return nil, runtimeError{message: operandsMustBeNumbers, line: be.Opt.Line}
 		}
-		return leftValue.(float64) < rightValue.(float64), nil
-	case spec.LessEqual:
-		if !isNumber(leftValue) || !isNumber(rightValue) {
-			return nil, runtimeError{message: operandsMustBeNumbers, line: be.Opt.Line}
+		switch be.Opt.Type {
+		case spec.Less:
+			return comparison < 0, nil
+		case spec.LessEqual:
+			return comparison <= 0, nil
+		case spec.Greater:
+			return comparison > 0, nil
+		default:
+			return comparison >= 0, nil
 		}
-		return leftValue.(float64) <= rightValue.(float64), nil
-	case spec.Greater:
-		if !isNumber(leftValue) || !isNumber(rightValue) {
-			return nil, runtimeError{message: operandsMustBeNumbers, line: be.Opt.Line}
+	case spec.EqualEqual, spec.BangEqual:
+		equal, equalError := intp.isEqual(leftValue, rightValue)
+		if equalError != nil {
+			return nil, withLine(equalError, be.Opt.Line)
 		}
-		return leftValue.(float64) > rightValue.(float64), nil
-	case spec.GreaterEqual:
-		if !isNumber(leftValue) || !isNumber(rightValue) {
-			return nil, runtimeError{message: operandsMustBeNumbers, line: be.Opt.Line}
-		}
-		return leftValue.(float64) >= rightValue.(float64), nil
-	case spec.EqualEqual:
-		return isEqual(leftValue, rightValue), nil
-	case spec.BangEqual:
-		return !isEqual(leftValue, rightValue), nil
+		return equal == (be.Opt.Type == spec.EqualEqual), nil
 	}
 
 	message := fmt.Sprintf("Unexpected type of binary expression: %s", be.Opt.Type.String())
@@ -106,18 +172,41 @@ func (intp *Interpreter) VisitAssignment(ae spec.AssignmentExpr) (any, error) {
 	if evalError != nil {
 		return nil, runtimeError{message: evalError.(runtimeError).message, line: ae.Identifier.Line, cause: evalError}
 	}
-	distance, contains := intp.locals[ae.Hash()]
-	if contains {
-		assignErr := intp.env.assignAt(distance, ae.Identifier.Lexeme, value)
-		if assignErr != nil {
-			return nil, runtimeError{message: assignErr.Error(), line: ae.Identifier.Line, cause: assignErr}
+	if assignErr := intp.assignVar(ae.Identifier, ae, value); assignErr != nil {
+		return nil, assignErr
+	}
+	return value, nil
+}
+
+func (intp *Interpreter) VisitTupleAssignment(tae spec.TupleAssignmentExpr) (any, error) {
+	value, evalError := tae.Expr.Eval(intp)
+	if evalError != nil {
+		return nil, evalError
+	}
+	values, unpackError := unpack(value, len(tae.Targets))
+	if unpackError != nil {
+		return nil, runtimeError{message: unpackError.Error(), line: tae.Paren.Line, cause: unpackError}
+	}
+	for i, target := range tae.Targets {
+		if assignErr := intp.assignVar(target.Identifier, target, values[i]); assignErr != nil {
+			return nil, assignErr
 		}
-	} else {
-		intp.globals.assign(ae.Identifier.Lexeme, value)
 	}
 	return value, nil
 }
 
+func (intp *Interpreter) VisitTuple(te spec.TupleExpr) (any, error) {
+	elements := []any{}
+	for _, element := range te.Elements {
+		value, valueError := element.Eval(intp)
+		if valueError != nil {
+			return nil, valueError
+		}
+		elements = append(elements, value)
+	}
+	return Tuple{elements: elements}, nil
+}
+
 func (intp *Interpreter) VisitLogical(le spec.LogicalExpr) (any, error) {
 	left, leftError := le.Left.Eval(intp)
 	if leftError != nil { return nil, leftError }
@@ -131,25 +220,55 @@ func (intp *Interpreter) VisitLogical(le spec.LogicalExpr) (any, error) {
 }
 
 func (intp *Interpreter) VisitCall(ce spec.CallExpr) (any, error) {
+	function, args, prepareError := intp.prepareCall(ce)
+	if prepareError != nil {
+		return nil, prepareError
+	}
+	return intp.invoke(function, args, ce.Paren)
+}
+
+// Evaluates the callee and the arguments of a call, and binds the arguments to the callee's parameters.
+func (intp *Interpreter) prepareCall(ce spec.CallExpr) (Callable, []any, error) {
 	callee, calleeError := ce.Callee.Eval(intp)
-	if calleeError != nil { return nil, calleeError }
+	if calleeError != nil { return nil, nil, calleeError }
 	args := []any{}
 	for _, arg := range ce.Args {
 		evaledArg, evalError := arg.Eval(intp)
 		if evalError != nil {
-			return nil, evalError
+			return nil, nil, evalError
 		}
 		args = append(args, evaledArg)
 	}
+	namedArgs := []namedArgument{}
+	for _, arg := range ce.NamedArgs {
+		evaledArg, evalError := arg.Value.Eval(intp)
+		if evalError != nil {
+			return nil, nil, evalError
+		}
+		namedArgs = append(namedArgs, namedArgument{name: arg.Name.Lexeme, value: evaledArg})
+	}
 	function, castOk := callee.(Callable)
 	if !castOk {
-		return nil, runtimeError{message: "can only call functions and classes", line: ce.Paren.Line}
+		return nil, nil, runtimeError{message: "can only call functions and classes", line: ce.Paren.Line}
 	}
-	if len(args) != int(function.arity()) {
-		msg := fmt.Sprintf("expected %v arguments but got %v", function.arity(), len(args))
-		return nil, runtimeError{message: msg, line: ce.Paren.Line}
+	boundArgs, bindError := bindArguments(function, args, namedArgs)
+	if bindError != nil {
+		return nil, nil, runtimeError{message: bindError.Error(), line: ce.Paren.Line, cause: bindError}
 	}
-	return function.call(intp, args)
+	return function, boundArgs, nil
+}
+
+func (intp *Interpreter) invoke(function Callable, args []any, paren spec.Token) (any, error) {
+	intp.traceCall(function, args, paren.Line, false)
+	value, callError := function.call(intp, args)
+	_, isNative := function.(*NativeFunction)
+	_, isClass := function.(*Class)
+	if (isNative || isClass) && callError != nil {
+		if _, isRuntimeError := callError.(runtimeError); !isRuntimeError {
+			return nil, runtimeError{message: callError.Error(), line: paren.Line, cause: callError}
+		}
+	}
+	return value, callError
 }
 
 func (intp *Interpreter) VisitGet(ge spec.GetExpr) (any, error) {
@@ -157,30 +276,61 @@ func (intp *Interpreter) VisitGet(ge spec.GetExpr) (any, error) {
 	if objectError != nil {
 		return nil, objectError
 	}
-	if instance, ok := object.(ClassInstance); ok {
-		value, valueError := instance.get(ge.Name.Lexeme)
-		if valueError != nil {
-			return nil, runtimeError{message: valueError.Error(), line: ge.Name.Line, cause: valueError}
-		}
-		return value, nil
+	return intp.getProperty(object, ge.Name)
+}
+
+func (intp *Interpreter) VisitOptionalGet(oge spec.OptionalGetExpr) (any, error) {
+	object, objectError := oge.Object.Eval(intp)
+	if objectError != nil {
+		return nil, objectError
+	}
+	if object == nil {
+		return nil, shortCircuit{}
+	}
+	return intp.getProperty(object, oge.Name)
+}
+
+func (intp *Interpreter) VisitOptionalChain(oce spec.OptionalChainExpr) (any, error) {
+	value, err := oce.Expr.Eval(intp)
+	if _, ok := err.(shortCircuit); ok {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (intp *Interpreter) VisitCoalesce(ce spec.CoalesceExpr) (any, error) {
+	left, leftError := ce.Left.Eval(intp)
+	if leftError != nil { return nil, leftError }
+	if left != nil {
+		return left, nil
 	}
-	return nil, runtimeError{message: "Only instances have properties", line: ge.Name.Line}
+	return ce.Right.Eval(intp)
 }
 
 func (intp *Interpreter) VisitSet(se spec.SetExpr) (any, error) {
+	if super, isSuper := se.Object.(spec.SuperExpr); isSuper {
+		return intp.setSuper(super, se.Value)
+	}
 	object, objectError := se.Object.Eval(intp)
 	if objectError != nil {
 		return nil, objectError
 	}
-	inst, castOk := object.(ClassInstance)
-	if !castOk {
-		return nil, runtimeError{message: "Only instances have fields", line: se.Name.Line}
+	inst, isInstance := object.(*ClassInstance)
+	class, isClass := object.(*Class)
+	if !isInstance && !isClass {
+		return nil, runtimeError{message: "Only instances and classes have fields", line: se.Name.Line}
 	}
 	value, valueError := se.Value.Eval(intp)
 	if valueError != nil {
 		return nil, valueError
 	}
-	inst.set(se.Name.Lexeme, value)
+	if isInstance {
+		if setError := inst.set(intp, se.Name.Lexeme, value); setError != nil {
+			return nil, withLine(setError, se.Name.Line)
+		}
+	} else {
+		class.set(se.Name.Lexeme, value)
+	}
 	return value, nil
 }
 
@@ -189,33 +339,301 @@ func (intp *Interpreter) VisitThis(te spec.ThisExpr) (any, error) {
 }
 
 func (intp *Interpreter) VisitSuper(se spec.SuperExpr) (any, error) {
+	superclass, instance, lookUpError := intp.lookUpSuper(se)
+	if lookUpError != nil {
+		return nil, lookUpError
+	}
+	method, ok := superclass.findMethod(se.Method.Lexeme)
+	if !ok {
+		return nil, runtimeError{message: fmt.Sprintf("undefined property %v", se.Method.Lexeme), line: se.Method.Line}
+	} else if method.declaration.IsAbstract {
+		message := fmt.Sprintf("Can't call abstract method '%v' of %v", se.Method.Lexeme, superclass.Name)
+		return nil, runtimeError{message: message, line: se.Method.Line}
+	}
+	value, getError := method.bindProperty(intp, instance)
+	if getError != nil {
+		return nil, withLine(getError, se.Method.Line)
+	}
+	return value, nil
+}
+
+// Evaluates `super.name = value`: calls the superclass setter on `this`, or assigns to the field if there is none.
+func (intp *Interpreter) setSuper(se spec.SuperExpr, valueExpr spec.Expr) (any, error) {
+	superclass, instance, lookUpError := intp.lookUpSuper(se)
+	if lookUpError != nil {
+		return nil, lookUpError
+	}
+	value, valueError := valueExpr.Eval(intp)
+	if valueError != nil {
+		return nil, valueError
+	}
+	if setter, contains := superclass.findSetter(se.Method.Lexeme); contains {
+		if _, callError := intp.callWith(setter.bind(instance), value); callError != nil {
+			return nil, withLine(callError, se.Method.Line)
+		}
+	} else {
+		instance.Fields[se.Method.Lexeme] = value
+	}
+	return value, nil
+}
+
+// Looks up the superclass and the instance that `super` refers to.
+func (intp *Interpreter) lookUpSuper(se spec.SuperExpr) (*Class, *ClassInstance, error) {
 	distance := intp.locals[se.Hash()]
 	superclass, scErr := intp.env.getAt(distance, "super")
 	if scErr != nil {
-		return nil, scErr
+		return nil, nil, scErr
 	}
 	instance, iErr := intp.env.getAt(distance - 1, "this")
 	if iErr != nil {
-		return nil, iErr
+		return nil, nil, iErr
 	}
-
-	superclassClass, scOk := superclass.(Class)
-	instanceInstance, iOk := instance.(ClassInstance)
+	superclassClass, scOk := superclass.(*Class)
+	instanceInstance, iOk := instance.(*ClassInstance)
 	if !scOk || !iOk {
-		return nil, nil // TODO
+		return nil, nil, runtimeError{message: "Can't use 'super' here", line: se.Keyword.Line}
 	}
+	return superclassClass, instanceInstance, nil
+}
 
-	method, ok := superclassClass.findMethod(se.Method.Lexeme)
-	if !ok {
-		return nil, nil // TODO
+func (intp *Interpreter) VisitLambda(le spec.LambdaExpr) (any, error) {
+	return &Function{declaration: le.Declaration, closure: intp.env, isInit: false, class: intp.currentClass}, nil
+}
+
+func (intp *Interpreter) VisitInterpolation(ie spec.InterpolationExpr) (any, error) {
+	var builder strings.Builder
+	for _, part := range ie.Parts {
+		value, valueError := part.Eval(intp)
+		if valueError != nil {
+			return nil, valueError
+		}
+		str, formatError := intp.stringify(value)
+		if formatError != nil {
+			return nil, withLine(formatError, ie.Quote.Line)
+		}
+		builder.WriteString(str)
+	}
+	return builder.String(), nil
+}
+
+func (intp *Interpreter) VisitRange(re spec.RangeExpr) (any, error) {
+	bounds := []spec.Expr{re.Start, re.End}
+	if re.Step != nil {
+		bounds = append(bounds, re.Step)
+	}
+	values := []int64{0, 0, 1}
+	for i, bound := range bounds {
+		value, valueError := bound.Eval(intp)
+		if valueError != nil {
+			return nil, valueError
+		}
+		integer, isInt := toInt64(value)
+		if !isInt {
+			return nil, runtimeError{message: "Range bounds and step must be integers", line: re.Opt.Line}
+		}
+		values[i] = integer
+	}
+	if values[2] == 0 {
+		return nil, runtimeError{message: "Range step can't be zero", line: re.Opt.Line}
+	}
+	return Range{start: values[0], end: values[1], step: values[2], inclusive: re.Opt.Type == spec.DotDot}, nil
+}
+
+func (intp *Interpreter) VisitList(le spec.ListExpr) (any, error) {
+	elements := []any{}
+	for _, element := range le.Elements {
+		value, valueError := element.Eval(intp)
+		if valueError != nil {
+			return nil, valueError
+		}
+		elements = append(elements, value)
 	}
-	return method.bind(instanceInstance), nil
+	return newList(elements), nil
+}
+
+func (intp *Interpreter) VisitMap(me spec.MapExpr) (any, error) {
+	dict := newMap()
+	for i := range me.Keys {
+		key, keyError := me.Keys[i].Eval(intp)
+		if keyError != nil {
+			return nil, keyError
+		}
+		value, valueError := me.Values[i].Eval(intp)
+		if valueError != nil {
+			return nil, valueError
+		}
+		if setError := dict.setAt(intp, key, value); setError != nil {
+			return nil, withLine(setError, me.Brace.Line)
+		}
+	}
+	return dict, nil
+}
+
+func (intp *Interpreter) VisitIndexGet(ige spec.IndexGetExpr) (any, error) {
+	object, objectError := ige.Object.Eval(intp)
+	if objectError != nil {
+		return nil, objectError
+	}
+	index, indexError := ige.Index.Eval(intp)
+	if indexError != nil {
+		return nil, indexError
+	}
+	var value any
+	var valueError error
+	switch object := object.(type) {
+	case *List:
+		value, valueError = object.getAt(index)
+	case *Map:
+		value, valueError = object.getAt(intp, index)
+	case Tuple:
+		value, valueError = object.getAt(index)
+	case string:
+		value, valueError = stringGetAt(object, index)
+	case *ClassInstance:
+		if result, isOverloaded, err := intp.callOperatorMethod(object, "__index__", ige.Bracket, index); isOverloaded {
+			return result, err
+		}
+		return nil, undefinedOperator("[]", ige.Bracket.Line, object)
+	default:
+		return nil, runtimeError{message: "Only lists, maps, tuples and strings can be indexed", line: ige.Bracket.Line}
+	}
+	if valueError != nil {
+		return nil, withLine(valueError, ige.Bracket.Line)
+	}
+	return value, nil
+}
+
+func (intp *Interpreter) VisitIndexSet(ise spec.IndexSetExpr) (any, error) {
+	object, objectError := ise.Object.Eval(intp)
+	if objectError != nil {
+		return nil, objectError
+	}
+	index, indexError := ise.Index.Eval(intp)
+	if indexError != nil {
+		return nil, indexError
+	}
+	value, valueError := ise.Value.Eval(intp)
+	if valueError != nil {
+		return nil, valueError
+	}
+	var setError error
+	switch object := object.(type) {
+	case *List:
+		setError = object.setAt(index, value)
+	case *Map:
+		setError = object.setAt(intp, index, value)
+	case Tuple:
+		return nil, runtimeError{message: "Tuples are immutable", line: ise.Bracket.Line}
+	case *ClassInstance:
+		if _, isOverloaded, err := intp.callOperatorMethod(object, "__setindex__", ise.Bracket, index, value); isOverloaded {
+			return value, err
+		}
+		return nil, undefinedOperator("[]=", ise.Bracket.Line, object)
+	default:
+		return nil, runtimeError{message: "Only lists and maps support index assignment", line: ise.Bracket.Line}
+	}
+	if setError != nil {
+		return nil, withLine(setError, ise.Bracket.Line)
+	}
+	return value, nil
+}
+
+func (intp *Interpreter) VisitSlice(se spec.SliceExpr) (any, error) {
+	object, objectError := se.Object.Eval(intp)
+	if objectError != nil {
+		return nil, objectError
+	}
+	var start, end any
+	if se.Start != nil {
+		value, valueError := se.Start.Eval(intp)
+		if valueError != nil {
+			return nil, valueError
+		}
+		start = value
+	}
+	if se.End != nil {
+		value, valueError := se.End.Eval(intp)
+		if valueError != nil {
+			return nil, valueError
+		}
+		end = value
+	}
+	var value any
+	var sliceError error
+	switch object := object.(type) {
+	case *List:
+		value, sliceError = object.slice(start, end)
+	case Tuple:
+		value, sliceError = object.slice(start, end)
+	case string:
+		value, sliceError = stringSlice(object, start, end)
+	default:
+		return nil, runtimeError{message: "Only lists, tuples and strings can be sliced", line: se.Bracket.Line}
+	}
+	if sliceError != nil {
+		return nil, runtimeError{message: sliceError.Error(), line: se.Bracket.Line, cause: sliceError}
+	}
+	return value, nil
 }
 
 // MARK: - Helpers
 
+// Assigns to the variable that the expression (a variable or assignment) was resolved to.
+func (intp *Interpreter) assignVar(name spec.Token, expr spec.Expr, value any) error {
+	var assignErr error
+	if distance, contains := intp.locals[expr.Hash()]; contains {
+		assignErr = intp.env.assignAt(distance, name.Lexeme, value)
+	} else {
+		assignErr = intp.globals.assign(name.Lexeme, value)
+	}
+	if assignErr != nil {
+		return runtimeError{message: assignErr.Error(), line: name.Line, cause: assignErr}
+	}
+	return nil
+}
+
+func (intp *Interpreter) getProperty(object any, name spec.Token) (any, error) {
+	var value any
+	var valueError error
+	switch object := object.(type) {
+	case *ClassInstance:
+		value, valueError = object.get(intp, name.Lexeme)
+	case *Class:
+		value, valueError = object.get(name.Lexeme)
+	case *Generator:
+		value, valueError = object.get(name.Lexeme)
+	case *List:
+		value, valueError = object.get(name.Lexeme)
+	case *Map:
+		value, valueError = object.get(name.Lexeme)
+	case Tuple:
+		value, valueError = object.get(name.Lexeme)
+	case string:
+		value, valueError = stringGet(object, name.Lexeme)
+	case Range:
+		value, valueError = object.get(name.Lexeme)
+	default:
+		return nil, runtimeError{message: "Only instances have properties", line: name.Line}
+	}
+	if valueError != nil {
+		if _, isRuntimeError := valueError.(runtimeError); isRuntimeError {
+			return nil, valueError
+		}
+		return nil, runtimeError{message: valueError.Error(), line: name.Line, cause: valueError}
+	}
+	return value, nil
+}
+
 const operandsMustBeNumbers = "Operands must be numbers"
 
+// Attaches a line to an error from native code. A runtime error, e.g. from a hook, already has the line it occurred at.
+func withLine(err error, line uint64) error {
+	if _, isRuntimeError := err.(runtimeError); isRuntimeError {
+		return err
+	}
+	return runtimeError{message: err.Error(), line: line, cause: err}
+}
+
 type runtimeError struct {
 	message string
 	line uint64
@@ -225,21 +643,53 @@ func (re runtimeError) Error() string {
 	return fmt.Sprintf("%s.\n[line %d]", re.message, re.line)
 }
 
+// Returned by an optional get (`?.`) on nil; caught by the enclosing OptionalChainExpr, which evaluates to nil.
+type shortCircuit struct{}
+func (sc shortCircuit) Error() string {
+	return "error: an optional chain was short-circuited, but the runtime did not catch this."
+}
+
 func isTruthy(value any) bool {
 	if value == false || value == nil { return false }
 	return true
 }
 
-func isEqual(a any, b any) bool {
-	if a == nil && b == nil { return true }
-	if a == nil { return false }
-	return a == b
-}
-
-func isNumber(value any) bool {
-	return reflect.TypeOf(value).Kind() == reflect.Float64
+// The name of a value's type as shown to Lox programs.
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "bool"
+	case int64, *big.Int, float64:
+		return "number"
+	case string:
+		return "string"
+	case *List:
+		return "list"
+	case *Map:
+		return "map"
+	case Tuple:
+		return "tuple"
+	case *Function, *NativeFunction:
+		return "function"
+	case *Class:
+		return "class"
+	case *Trait:
+		return "trait"
+	case *Interface:
+		return "interface"
+	case *ClassInstance:
+		return "instance"
+	case *Generator:
+		return "generator"
+	case Range:
+		return "range"
+	}
+	return "unknown"
 }
 
 func isString(value any) bool {
-	return reflect.TypeOf(value).Kind() == reflect.String
+	_, ok := value.(string)
+	return ok
 }
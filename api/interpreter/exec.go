@@ -2,7 +2,6 @@ package interpreter
 
 import (
 	"fmt"
-	"reflect"
 
 	"github.com/codecrafters-io/interpreter-starter-go/spec"
 )
@@ -10,23 +9,26 @@ import (
 func (intp *Interpreter) VisitPrint(ps spec.PrintStmt) error {
 	value, evalError := ps.Expr.Eval(intp)
 	if evalError != nil { return evalError }
-	if value == nil {
-		fmt.Println("nil")
-	} else if reflect.TypeOf(value).Kind() == reflect.Float64 {
-		fmt.Println(float64ToString(value.(float64)))
-	} else {
-		fmt.Println(value)
+	str, formatError := intp.stringify(value)
+	if formatError != nil {
+		return withLine(formatError, ps.Keyword.Line)
 	}
+	fmt.Println(str)
 	return nil
 }
-func float64ToString(number float64) string {
-	if number == float64(int(number)) {
-		return fmt.Sprintf("%.0f", number)
-	}
-	return fmt.Sprintf("%g", number)
+// Formats a value like `print`, but without calling any hooks.
+func stringify(value any) string {
+	str, _ := formatValue(nil, value, make(map[any]bool), false)
+	return str
+}
+
+// Formats a value the same way `print` does.
+func Stringify(value any) string {
+	return stringify(value)
 }
 
 
+
 func (intp *Interpreter) VisitExpr(es spec.ExprStmt) error {
 	if es.Expr == nil { return nil }
 	if _, evalError := es.Expr.Eval(intp); evalError != nil {
@@ -42,6 +44,19 @@ func (intp *Interpreter) VisitDeclare(ds spec.DeclareStmt) error {
 	return nil
 }
 
+func (intp *Interpreter) VisitDeclareTuple(dts spec.DeclareTupleStmt) error {
+	value, evalError := dts.Expr.Eval(intp)
+	if evalError != nil { return evalError }
+	values, unpackError := unpack(value, len(dts.Identifiers))
+	if unpackError != nil {
+		return runtimeError{message: unpackError.Error(), line: dts.Paren.Line, cause: unpackError}
+	}
+	for i, identifier := range dts.Identifiers {
+		intp.env.define(identifier.Lexeme, values[i])
+	}
+	return nil
+}
+
 func (intp *Interpreter) VisitBlock(bs spec.BlockStmt) error {
 	env := newEnvWithParent(intp.env)
 	return intp.ExecBlock(&bs.Statements, &env)
@@ -91,10 +106,11 @@ func (intp *Interpreter) VisitWhile(ws spec.WhileStmt) error {
 func (intp *Interpreter) VisitFunc(fs spec.FuncStmt) error {
 	intp.env.define(
 		fs.Name.Lexeme,
-		Function {
+		&Function {
 			declaration: fs,
 			closure: intp.env,
 			isInit: false,
+			class: intp.currentClass,
 		},
 	)
 	return nil
@@ -104,6 +120,20 @@ func (intp *Interpreter) VisitReturn(rs spec.ReturnStmt) error {
 	if rs.Expr == nil {
 		return Return{value: nil}
 	}
+	if call, isCall := rs.Expr.(spec.CallExpr); isCall && intp.tailCalls[call.Hash()] {
+		function, args, prepareError := intp.prepareCall(call)
+		if prepareError != nil {
+			return prepareError
+		}
+		if fn, isFunction := function.(*Function); isFunction && !fn.isInit && !fn.declaration.IsGenerator {
+			return tailCall{function: fn, args: args, line: call.Paren.Line}
+		}
+		value, callError := intp.invoke(function, args, call.Paren)
+		if callError != nil {
+			return callError
+		}
+		return Return{value: value}
+	}
 	value, evalError := rs.Expr.Eval(intp)
 	if evalError != nil {
 		return evalError
@@ -118,29 +148,146 @@ func (intp *Interpreter) VisitClass(cs spec.ClassStmt) error {
 		if sclassError != nil {
 			return sclassError
 		}
-		if srclass, ok := sclass.(Class); ok {
-			superclass = &srclass
+		if srclass, ok := sclass.(*Class); ok {
+			superclass = srclass
 		} else {
 			return runtimeError{message: "Superclass must be a class", line: cs.Superclass.Identifier.Line}
 		}
 	}
 
+	traits := []*Trait{}
+	for _, traitExpr := range cs.Traits {
+		value, traitError := traitExpr.Eval(intp)
+		if traitError != nil {
+			return traitError
+		}
+		trait, ok := value.(*Trait)
+		if !ok {
+			message := fmt.Sprintf("'%v' is not a trait", traitExpr.Identifier.Lexeme)
+			return runtimeError{message: message, line: traitExpr.Identifier.Line}
+		}
+		traits = append(traits, trait)
+	}
+
+	interfaces := []*Interface{}
+	for _, interfaceExpr := range cs.Interfaces {
+		value, interfaceError := interfaceExpr.Eval(intp)
+		if interfaceError != nil {
+			return interfaceError
+		}
+		iface, ok := value.(*Interface)
+		if !ok {
+			message := fmt.Sprintf("'%v' is not an interface", interfaceExpr.Identifier.Lexeme)
+			return runtimeError{message: message, line: interfaceExpr.Identifier.Line}
+		}
+		interfaces = append(interfaces, iface)
+	}
+
 	intp.env.define(cs.Name.Lexeme, nil)
 
 	if cs.Superclass != nil {
 		env := newEnvWithParent(intp.env)
-		env.define("super", *superclass)
+		env.define("super", superclass)
 		intp.env = &env
 		defer func() { intp.env = env.parent }()
 	}
 
-	methods := make(map[string]Function)
-	for _, method := range cs.Methods {
-		methodFunc := Function{declaration: method, closure: intp.env, isInit: method.Name.Lexeme == "init"}
-		methods[method.Name.Lexeme] = methodFunc
+	class := &Class{
+		Name: cs.Name.Lexeme,
+		Methods: make(map[string]*Function),
+		Superclass: superclass,
+		StaticMethods: make(map[string]*Function),
+		StaticFields: make(map[string]any),
+		Setters: make(map[string]*Function),
+		PrivateMethods: make(map[string]*Function),
+		Fields: cs.Fields,
+		env: intp.env,
 	}
 
-	class := Class{Name: cs.Name.Lexeme, Methods: methods, Superclass: superclass}
+	for _, method := range cs.Methods {
+		methodFunc := &Function{declaration: method, closure: intp.env, isInit: method.Name.Lexeme == "init", class: class}
+		if method.Name.Type == spec.PrivateIdentifier {
+			class.PrivateMethods[method.Name.Lexeme] = methodFunc
+		} else {
+			class.Methods[method.Name.Lexeme] = methodFunc
+		}
+	}
+	if traitError := class.includeTraits(traits, cs.Name.Line); traitError != nil {
+		return traitError
+	}
+	if overrideError := class.checkAbstractOverrides(cs.Name.Line); overrideError != nil {
+		return overrideError
+	}
+	if interfaceError := class.checkInterfaces(interfaces, cs.Name.Line); interfaceError != nil {
+		return interfaceError
+	}
+	for _, method := range cs.StaticMethods {
+		class.StaticMethods[method.Name.Lexeme] = &Function{declaration: method, closure: intp.env, isInit: false, class: class}
+	}
+	for _, setter := range cs.Setters {
+		class.Setters[setter.Name.Lexeme] = &Function{declaration: setter, closure: intp.env, isInit: false, class: class}
+	}
 	intp.env.assign(cs.Name.Lexeme, class)
+
+	// static fields are initialized in order once the class exists, so that they can refer to it
+	for _, field := range cs.StaticFields {
+		value, evalError := field.Expr.Eval(intp)
+		if evalError != nil {
+			return evalError
+		}
+		class.set(field.Identifier.Lexeme, value)
+	}
+	return nil
+}
+
+func (intp *Interpreter) VisitTrait(ts spec.TraitStmt) error {
+	trait := &Trait{Name: ts.Name.Lexeme, Methods: make(map[string]*Function)}
+	for _, method := range ts.Methods {
+		trait.Methods[method.Name.Lexeme] = &Function{declaration: method, closure: intp.env, isInit: method.Name.Lexeme == "init"}
+	}
+	intp.env.define(ts.Name.Lexeme, trait)
+	return nil
+}
+
+func (intp *Interpreter) VisitInterface(is spec.InterfaceStmt) error {
+	intp.env.define(is.Name.Lexeme, &Interface{Name: is.Name.Lexeme, Methods: is.Methods})
+	return nil
+}
+
+func (intp *Interpreter) VisitYield(ys spec.YieldStmt) error {
+	value, evalError := ys.Expr.Eval(intp)
+	if evalError != nil {
+		return evalError
+	}
+	if intp.generator == nil {
+		return runtimeError{message: "Can only yield inside a generator", line: ys.Keyword.Line}
+	}
+	intp.generator.yield(value)
 	return nil
 }
+
+func (intp *Interpreter) VisitForIn(fis spec.ForInStmt) error {
+	value, evalError := fis.Iterable.Eval(intp)
+	if evalError != nil {
+		return evalError
+	}
+	iterable, ok := value.(iterable)
+	if !ok {
+		return runtimeError{message: "Can only iterate over iterable values", line: fis.Keyword.Line}
+	}
+	iterator := iterable.iterator()
+	for {
+		element, hasNext, nextError := iterator.next()
+		if nextError != nil {
+			return nextError
+		}
+		if !hasNext {
+			return nil
+		}
+		env := newEnvWithParent(intp.env)
+		env.define(fis.Identifier.Lexeme, element)
+		if err := intp.ExecBlock(&[]spec.Stmt{fis.Body}, &env); err != nil {
+			return err
+		}
+	}
+}
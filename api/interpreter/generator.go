@@ -0,0 +1,129 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A suspended call to a function whose body contains `yield`. The body runs on its own goroutine, with its own copy
+// of the interpreter, and hands control back and forth with the caller over unbuffered channels, so only one side is
+// ever running at a time.
+//
+// A generator that is abandoned before it finishes leaves its goroutine blocked until the program exits.
+type Generator struct { // implements iterable, iterator
+	function *Function
+	intp Interpreter
+	env *environment
+	started bool
+	running bool
+	finished bool
+	resume chan struct{}
+	steps chan generatorStep
+	// The step produced by looking ahead to answer `done`, which the next call to `next()` returns.
+	peeked *generatorStep
+}
+
+type generatorStep struct {
+	value any
+	done bool
+	err error
+}
+
+func newGenerator(function *Function, intp *Interpreter, env *environment) *Generator {
+	gen := &Generator{
+		function: function,
+		intp: *intp,
+		env: env,
+		resume: make(chan struct{}),
+		steps: make(chan generatorStep),
+	}
+	gen.intp.generator = gen
+	return gen
+}
+
+func (gen *Generator) String() string {
+	return fmt.Sprintf("<generator %v>", gen.function.declaration.Name.Lexeme)
+}
+
+func (gen *Generator) get(name string) (any, error) {
+	switch name {
+	case "next":
+		return &NativeFunction{
+			_name: "next",
+			_params: []parameter{},
+			_func: func(intp *Interpreter, args []any) (any, error) {
+				value, _, err := gen.next()
+				return value, err
+			},
+		}, nil
+	case "done":
+		step, err := gen.peek()
+		if err != nil {
+			return nil, err
+		}
+		return step.done, nil
+	}
+	return nil, fmt.Errorf("undefined property %v", name)
+}
+
+func (gen *Generator) iterator() iterator {
+	return gen
+}
+
+// Returns the next yielded value, or nil and false once the body has finished.
+func (gen *Generator) next() (any, bool, error) {
+	step, err := gen.peek()
+	gen.peeked = nil
+	if err != nil {
+		return nil, false, err
+	}
+	return step.value, !step.done, nil
+}
+
+func (gen *Generator) peek() (generatorStep, error) {
+	if gen.peeked == nil {
+		step, err := gen.advance()
+		if err != nil {
+			return step, err
+		}
+		gen.peeked = &step
+	}
+	return *gen.peeked, gen.peeked.err
+}
+
+// Runs the body until the next yield or until it finishes.
+func (gen *Generator) advance() (generatorStep, error) {
+	if gen.finished {
+		return generatorStep{done: true}, nil
+	}
+	if gen.running {
+		return generatorStep{}, errors.New("generator is already running")
+	}
+	gen.running = true
+	if !gen.started {
+		gen.started = true
+		go gen.run()
+	} else {
+		gen.resume <- struct{}{}
+	}
+	step := <-gen.steps
+	gen.running = false
+	if step.done {
+		gen.finished = true
+	}
+	return step, nil
+}
+
+func (gen *Generator) run() {
+	execError := gen.intp.ExecBlock(&gen.function.declaration.Body, gen.env)
+	if _, isReturn := execError.(Return); isReturn {
+		execError = nil
+	}
+	gen.steps <- generatorStep{done: true, err: execError}
+}
+
+// Called from the generator's goroutine: hands the value to the caller and waits to be resumed.
+func (gen *Generator) yield(value any) {
+	gen.steps <- generatorStep{value: value}
+	<-gen.resume
+}
@@ -9,22 +9,25 @@ func newGlobalsEnv() environment {
 	for _, fn := range nativeFunctions {
 		env.define(fn._name, fn)
 	}
+	for _, fn := range reflectionFunctions {
+		env.define(fn._name, fn)
+	}
 	return env
 }
 
-var nativeFunctions = []NativeFunction {
+var nativeFunctions = []*NativeFunction {
 	{
 		_name: "clock",
-		_arity: 0,
-		_func: func(args []any) any {
-			return float64(time.Now().Unix())
+		_params: []parameter{},
+		_func: func(intp *Interpreter, args []any) (any, error) {
+			return float64(time.Now().Unix()), nil
 		},
 	},
 	{
 		_name: "echo",
-		_arity: 1,
-		_func: func(args []any) any {
-			return args[0]
+		_params: []parameter{{name: "value"}},
+		_func: func(intp *Interpreter, args []any) (any, error) {
+			return args[0], nil
 		},
 	},
 }
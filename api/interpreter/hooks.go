@@ -0,0 +1,232 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/spec"
+)
+
+// Classes customize how their instances are printed, compared and hashed by defining the methods toString(),
+// equals(other) and hash(). Anything that prints, compares or hashes values goes through the interpreter, so that these
+// hooks are called wherever they apply, including inside collections.
+
+// MARK: - Formatting
+
+// Formats a value the way `print` does, calling toString() on instances that define it.
+func (intp *Interpreter) stringify(value any) (string, error) {
+	return formatValue(intp, value, intp.formatting, false)
+}
+
+// Formats a value; strings nested in a collection are quoted. Without an interpreter, no hooks are called. A collection
+// or instance that is already being formatted further up shows up as "...", so that values containing themselves can
+// be printed.
+func formatValue(intp *Interpreter, value any, inProgress map[any]bool, isNested bool) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "nil", nil
+	case string:
+		if isNested {
+			return "\"" + value + "\"", nil
+		}
+		return value, nil
+	case *List:
+		if inProgress[value] {
+			return "[...]", nil
+		}
+		inProgress[value] = true
+		defer delete(inProgress, value)
+		elements, formatError := formatElements(intp, value.elements, inProgress)
+		return "[" + strings.Join(elements, ", ") + "]", formatError
+	case Tuple:
+		elements, formatError := formatElements(intp, value.elements, inProgress)
+		if len(elements) == 1 {
+			return "(" + elements[0] + ",)", formatError
+		}
+		return "(" + strings.Join(elements, ", ") + ")", formatError
+	case *Map:
+		if inProgress[value] {
+			return "{...}", nil
+		}
+		inProgress[value] = true
+		defer delete(inProgress, value)
+		entries := []string{}
+		for _, entry := range value.entries {
+			pair, formatError := formatElements(intp, []any{entry.key, entry.value}, inProgress)
+			if formatError != nil {
+				return "", formatError
+			}
+			entries = append(entries, pair[0] + ": " + pair[1])
+		}
+		return "{" + strings.Join(entries, ", ") + "}", nil
+	case *ClassInstance:
+		method, hasToString := value.Class.findMethod("toString")
+		if intp == nil || !hasToString {
+			return value.String(), nil
+		}
+		if inProgress[value] {
+			return "...", nil
+		}
+		inProgress[value] = true
+		defer delete(inProgress, value)
+		result, callError := intp.callWith(method.bind(value))
+		if callError != nil {
+			return "", callError
+		}
+		if str, isString := result.(string); isString {
+			return str, nil
+		}
+		return "", fmt.Errorf("toString() of %v must return a string", value)
+	}
+	if numberString, isNumber := spec.FormatNumber(value); isNumber {
+		return numberString, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+func formatElements(intp *Interpreter, elements []any, inProgress map[any]bool) ([]string, error) {
+	formatted := []string{}
+	for _, element := range elements {
+		str, formatError := formatValue(intp, element, inProgress, true)
+		if formatError != nil {
+			return nil, formatError
+		}
+		formatted = append(formatted, str)
+	}
+	return formatted, nil
+}
+
+// MARK: - Equality
+
+// Compares two values for equality. Instances are compared by calling equals() if their class defines it, and by
+// identity otherwise; tuples are compared element by element, and numbers by value regardless of their kind.
+func (intp *Interpreter) isEqual(a any, b any) (bool, error) {
+	if inst, ok := a.(*ClassInstance); ok {
+		if _, hasEquals := inst.Class.findMethod("equals"); hasEquals {
+			return intp.callEquals(inst, b)
+		}
+	}
+	if inst, ok := b.(*ClassInstance); ok {
+		if _, hasEquals := inst.Class.findMethod("equals"); hasEquals {
+			return intp.callEquals(inst, a)
+		}
+	}
+	if tuple, ok := a.(Tuple); ok {
+		other, ok := b.(Tuple)
+		if !ok || len(tuple.elements) != len(other.elements) {
+			return false, nil
+		}
+		for i := range tuple.elements {
+			if equal, equalError := intp.isEqual(tuple.elements[i], other.elements[i]); !equal || equalError != nil {
+				return false, equalError
+			}
+		}
+		return true, nil
+	}
+	if _, ok := b.(Tuple); ok {
+		return false, nil
+	}
+	if isNumber(a) && isNumber(b) {
+		comparison, comparable := compareNumbers(a, b)
+		return comparable && comparison == 0, nil
+	}
+	return a == b, nil
+}
+
+func (intp *Interpreter) callEquals(inst *ClassInstance, other any) (bool, error) {
+	result, callError := intp.callHook(inst, "equals", other)
+	if callError != nil {
+		return false, callError
+	}
+	return isTruthy(result), nil
+}
+
+// MARK: - Hashing
+
+// Converts a value to a Go map key. Values that are equal convert to the same key, so `1` and `1.0` are the same key.
+// Nil, booleans, numbers, strings and tuples of hashable values are hashed by value; instances by calling hash() if
+// their class defines it, and by identity otherwise, as are classes and functions. Other values can't be hashed.
+func (intp *Interpreter) hashKey(key any) (any, error) {
+	switch key := key.(type) {
+	case nil, bool, string, int64:
+		return key, nil
+	case *big.Int:
+		return bigKey{digits: key.String()}, nil
+	case float64:
+		if integer, isInt := toInt64(key); isInt {
+			return integer, nil
+		} else if !math.IsInf(key, 0) && key == math.Trunc(key) {
+			integer, _ := big.NewFloat(key).Int(nil)
+			return bigKey{digits: integer.String()}, nil
+		}
+		return key, nil
+	case Tuple:
+		encoded := strings.Builder{}
+		for _, element := range key.elements {
+			hashed, hashError := intp.hashKey(element)
+			if hashError != nil {
+				return nil, hashError
+			}
+			part := fmt.Sprintf("%T:%v", hashed, hashed)
+			fmt.Fprintf(&encoded, "%d:%v;", len(part), part)
+		}
+		return tupleKey{encoded: encoded.String()}, nil
+	case *ClassInstance:
+		_, hasHash := key.Class.findMethod("hash")
+		_, hasEquals := key.Class.findMethod("equals")
+		if !hasHash && hasEquals {
+			return nil, fmt.Errorf("Unhashable type: %v defines equals() but not hash()", key)
+		} else if !hasHash {
+			return key, nil
+		}
+		hash, callError := intp.callHook(key, "hash")
+		if callError != nil {
+			return nil, callError
+		}
+		if _, isInstance := hash.(*ClassInstance); isInstance {
+			return nil, fmt.Errorf("hash() of %v must not return an instance", key)
+		}
+		return intp.hashKey(hash)
+	case *Class, *Trait, *Interface, *Function, *NativeFunction:
+		return key, nil
+	}
+	return nil, fmt.Errorf("Unhashable type: %v", typeName(key))
+}
+
+type tupleKey struct {
+	encoded string
+}
+
+type bigKey struct {
+	digits string
+}
+
+// MARK: - Hook calls
+
+// A call to a hook that is in progress. A hook that calls itself on the same instance with the same argument would
+// never return, and fails instead.
+type hookCall struct {
+	receiver *ClassInstance
+	name string
+	argument any
+}
+
+func (intp *Interpreter) callHook(inst *ClassInstance, name string, args ...any) (any, error) {
+	method, _ := inst.Class.findMethod(name)
+	call := hookCall{receiver: inst, name: name}
+	if len(args) > 0 {
+		if _, isTuple := args[0].(Tuple); isTuple {
+			// tuples can't be map keys, so calls with a tuple argument aren't tracked
+			return intp.callWith(method.bind(inst), args...)
+		}
+		call.argument = args[0]
+	}
+	if intp.hookCalls[call] {
+		return nil, fmt.Errorf("%v() of %v calls itself recursively", name, inst)
+	}
+	intp.hookCalls[call] = true
+	defer delete(intp.hookCalls, call)
+	return intp.callWith(method.bind(inst), args...)
+}
@@ -3,6 +3,7 @@ package interpreter
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/codecrafters-io/interpreter-starter-go/spec"
 )
@@ -11,17 +12,54 @@ type Interpreter struct { // implements spec.ExprVisitor[any, error], spec.StmtV
 	env *environment
 	globals *environment
 	locals map[uint64]int // map of spec.Expr.Hash() -> int
+	generator *Generator // the generator whose body this interpreter is running, if any
+	tailCalls map[uint64]bool // set of spec.CallExpr.Hash() of calls in tail position
+	trace bool
+	formatting map[any]bool // set of collections and instances being formatted
+	hookCalls map[hookCall]bool // set of hook calls in progress
+	currentClass *Class // the class declaring the running function, whose private members it can access
 }
 
 func NewInterpreter() Interpreter {
 	env := newGlobalsEnv()
-	return Interpreter{env: &env, globals: &env, locals: make(map[uint64]int)}
+	return Interpreter{
+		env: &env,
+		globals: &env,
+		locals: make(map[uint64]int),
+		tailCalls: make(map[uint64]bool),
+		formatting: make(map[any]bool),
+		hookCalls: make(map[hookCall]bool),
+	}
 }
 
 func (intp *Interpreter) Resolve(expr spec.Expr, depth int) {
 	intp.locals[expr.Hash()] = depth
 }
 
+func (intp *Interpreter) ResolveTailCall(expr spec.CallExpr) {
+	intp.tailCalls[expr.Hash()] = true
+}
+
+// Enables printing every call, including the ones eliminated as tail calls, to stderr.
+func (intp *Interpreter) SetTrace(enabled bool) {
+	intp.trace = enabled
+}
+
+func (intp *Interpreter) traceCall(function Callable, args []any, line uint64, isTailCall bool) {
+	if !intp.trace {
+		return
+	}
+	argStrings := []string{}
+	for _, arg := range args {
+		argStrings = append(argStrings, stringify(arg))
+	}
+	suffix := ""
+	if isTailCall {
+		suffix = " (tail call)"
+	}
+	fmt.Fprintf(os.Stderr, "[line %v] call %v(%v)%v\n", line, function, strings.Join(argStrings, ", "), suffix)
+}
+
 func (intp *Interpreter) lookUpVar(name spec.Token, expr spec.Expr) (any, error) {
 	distance, contains := intp.locals[expr.Hash()]
 	if contains {
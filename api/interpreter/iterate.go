@@ -0,0 +1,11 @@
+package interpreter
+
+// Implemented by runtime values that can be looped over with `for (var x in value)`.
+type iterable interface {
+	iterator() iterator
+}
+
+type iterator interface {
+	// Returns the next element, or false as the second value once there are no more elements.
+	next() (any, bool, error)
+}
@@ -0,0 +1,253 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// A mutable, ordered sequence of values.
+type List struct { // implements iterable
+	elements []any
+}
+
+func newList(elements []any) *List {
+	return &List{elements: elements}
+}
+
+func (list *List) String() string {
+	return stringify(list)
+}
+
+func (list *List) getAt(index any) (any, error) {
+	i, indexError := toIndex(index, len(list.elements))
+	if indexError != nil {
+		return nil, indexError
+	}
+	return list.elements[i], nil
+}
+
+func (list *List) setAt(index any, value any) error {
+	i, indexError := toIndex(index, len(list.elements))
+	if indexError != nil {
+		return indexError
+	}
+	list.elements[i] = value
+	return nil
+}
+
+func (list *List) slice(start any, end any) (any, error) {
+	from, to, boundsError := sliceBounds(start, end, len(list.elements))
+	if boundsError != nil {
+		return nil, boundsError
+	}
+	elements := make([]any, to - from)
+	copy(elements, list.elements[from:to])
+	return newList(elements), nil
+}
+
+// MARK: - Methods
+
+func (list *List) get(name string) (any, error) {
+	switch name {
+	case "push":
+		return nativeMethod(name, []parameter{{name: "value"}}, func(intp *Interpreter, args []any) (any, error) {
+			list.elements = append(list.elements, args[0])
+			return nil, nil
+		}), nil
+	case "pop":
+		return nativeMethod(name, []parameter{}, func(intp *Interpreter, args []any) (any, error) {
+			if len(list.elements) == 0 {
+				return nil, errors.New("Can't pop from an empty list")
+			}
+			last := list.elements[len(list.elements) - 1]
+			list.elements = list.elements[:len(list.elements) - 1]
+			return last, nil
+		}), nil
+	case "insert":
+		params := []parameter{{name: "index"}, {name: "value"}}
+		return nativeMethod(name, params, func(intp *Interpreter, args []any) (any, error) {
+			i, indexError := toInsertionIndex(args[0], len(list.elements))
+			if indexError != nil {
+				return nil, indexError
+			}
+			list.elements = append(list.elements, nil)
+			copy(list.elements[i+1:], list.elements[i:])
+			list.elements[i] = args[1]
+			return nil, nil
+		}), nil
+	case "remove":
+		return nativeMethod(name, []parameter{{name: "index"}}, func(intp *Interpreter, args []any) (any, error) {
+			i, indexError := toIndex(args[0], len(list.elements))
+			if indexError != nil {
+				return nil, indexError
+			}
+			removed := list.elements[i]
+			list.elements = append(list.elements[:i], list.elements[i+1:]...)
+			return removed, nil
+		}), nil
+	case "len":
+		return nativeMethod(name, []parameter{}, func(intp *Interpreter, args []any) (any, error) {
+			return int64(len(list.elements)), nil
+		}), nil
+	case "contains":
+		return nativeMethod(name, []parameter{{name: "value"}}, func(intp *Interpreter, args []any) (any, error) {
+			for _, element := range list.elements {
+				if equal, equalError := intp.isEqual(element, args[0]); equal || equalError != nil {
+					return equal, equalError
+				}
+			}
+			return false, nil
+		}), nil
+	case "sort":
+		params := []parameter{{name: "comparator", optional: true}}
+		return nativeMethod(name, params, func(intp *Interpreter, args []any) (any, error) {
+			return nil, list.sort(intp, args[0])
+		}), nil
+	}
+	return nil, fmt.Errorf("undefined property %v", name)
+}
+
+// Sorts the list in place. Without a comparator, the elements must be all numbers or all strings; a comparator
+// receives two elements and returns a negative number if the first one should come first.
+func (list *List) sort(intp *Interpreter, comparator any) error {
+	var sortError error
+	var less func(a, b any) bool
+	if comparator == nil {
+		if kindError := checkSortable(list.elements); kindError != nil {
+			return kindError
+		}
+		less = func(a, b any) bool {
+			if isString(a) {
+				return a.(string) < b.(string)
+			}
+			comparison, _ := compareNumbers(a, b)
+			return comparison < 0
+		}
+	} else if function, ok := comparator.(Callable); ok {
+		less = func(a, b any) bool {
+			result, callError := intp.callWith(function, a, b)
+			if callError != nil {
+				sortError = callError
+				return false
+			}
+			if !isNumber(result) {
+				sortError = errors.New("Comparator must return a number")
+				return false
+			}
+			comparison, _ := compareNumbers(result, int64(0))
+			return comparison < 0
+		}
+	} else {
+		return errors.New("Comparator must be a function")
+	}
+	sorted := make([]any, len(list.elements))
+	copy(sorted, list.elements)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sortError == nil && less(sorted[i], sorted[j])
+	})
+	if sortError != nil {
+		return sortError
+	}
+	list.elements = sorted
+	return nil
+}
+
+// Checks that the elements are either all numbers or all strings.
+func checkSortable(elements []any) error {
+	kind := ""
+	for _, element := range elements {
+		if !isNumber(element) && !isString(element) {
+			return errors.New("Can only sort numbers or strings without a comparator")
+		}
+		if kind != "" && kind != typeName(element) {
+			return errors.New("Can't sort a mix of numbers and strings without a comparator")
+		}
+		kind = typeName(element)
+	}
+	return nil
+}
+
+// MARK: - Iteration
+
+func (list *List) iterator() iterator {
+	return &listIterator{list: list}
+}
+
+type listIterator struct {
+	list *List
+	position int
+}
+func (it *listIterator) next() (any, bool, error) {
+	if it.position >= len(it.list.elements) {
+		return nil, false, nil
+	}
+	element := it.list.elements[it.position]
+	it.position++
+	return element, true, nil
+}
+
+// MARK: - Helpers
+
+func nativeMethod(name string, params []parameter, fn func(intp *Interpreter, args []any) (any, error)) *NativeFunction {
+	return &NativeFunction{_name: name, _params: params, _func: fn}
+}
+
+// Converts an index value to a position in a sequence of the given length, counting negative indices from the end.
+func toIndex(index any, length int) (int, error) {
+	number, isInt := toInt64(index)
+	if !isInt {
+		if _, isBig := index.(*big.Int); isBig {
+			return 0, errors.New("Index out of range")
+		}
+		return 0, errors.New("Index must be an integer")
+	}
+	i := int(number)
+	if i < 0 {
+		i += length
+	}
+	if i < 0 || i >= length {
+		return 0, errors.New("Index out of range")
+	}
+	return i, nil
+}
+
+// Like toIndex, but also allows the position right after the last element.
+func toInsertionIndex(index any, length int) (int, error) {
+	if number, isInt := toInt64(index); isInt && int(number) == length {
+		return length, nil
+	}
+	return toIndex(index, length)
+}
+
+// Converts slice bounds to positions in a sequence of the given length. Omitted (nil) bounds default to the start and
+// end of the sequence, negative bounds count from the end, and bounds out of range are clamped.
+func sliceBounds(start any, end any, length int) (int, int, error) {
+	bound := func(value any, fallback int) (int, error) {
+		if value == nil {
+			return fallback, nil
+		}
+		number, isInt := toInt64(value)
+		if !isInt {
+			if huge, isBig := value.(*big.Int); isBig {
+				return max(0, min(length * huge.Sign(), length)), nil
+			}
+			return 0, errors.New("Slice bounds must be integers")
+		}
+		i := int(number)
+		if i < 0 {
+			i += length
+		}
+		return max(0, min(i, length)), nil
+	}
+	from, fromError := bound(start, 0)
+	if fromError != nil {
+		return 0, 0, fromError
+	}
+	to, toError := bound(end, length)
+	if toError != nil {
+		return 0, 0, toError
+	}
+	return from, max(from, to), nil
+}
@@ -0,0 +1,141 @@
+package interpreter
+
+import (
+	"fmt"
+)
+
+// An insertion-ordered dictionary.
+type Map struct { // implements iterable
+	entries []mapEntry
+	// map of hashKey(key) -> positions in entries of the keys with that hash; keys in the same bucket are told apart
+	// with isEqual, as instances with a hash() hook may hash the same without being equal
+	positions map[any][]int
+}
+
+type mapEntry struct {
+	key any
+	hashed any
+	value any
+}
+
+func newMap() *Map {
+	return &Map{entries: []mapEntry{}, positions: make(map[any][]int)}
+}
+
+func (dict *Map) String() string {
+	return stringify(dict)
+}
+
+// Finds the position of the key in entries, or returns -1 if the map does not contain the key. Also returns the key's
+// hash.
+func (dict *Map) find(intp *Interpreter, key any) (int, any, error) {
+	hashed, hashError := intp.hashKey(key)
+	if hashError != nil {
+		return -1, nil, hashError
+	}
+	for _, position := range dict.positions[hashed] {
+		if equal, equalError := intp.isEqual(dict.entries[position].key, key); equal || equalError != nil {
+			return position, hashed, equalError
+		}
+	}
+	return -1, hashed, nil
+}
+
+// Returns the value for the key, or nil if the map does not contain the key.
+func (dict *Map) getAt(intp *Interpreter, key any) (any, error) {
+	position, _, findError := dict.find(intp, key)
+	if findError != nil || position < 0 {
+		return nil, findError
+	}
+	return dict.entries[position].value, nil
+}
+
+func (dict *Map) setAt(intp *Interpreter, key any, value any) error {
+	position, hashed, findError := dict.find(intp, key)
+	if findError != nil {
+		return findError
+	}
+	if position >= 0 {
+		dict.entries[position].value = value
+	} else {
+		dict.positions[hashed] = append(dict.positions[hashed], len(dict.entries))
+		dict.entries = append(dict.entries, mapEntry{key: key, hashed: hashed, value: value})
+	}
+	return nil
+}
+
+func (dict *Map) has(intp *Interpreter, key any) (bool, error) {
+	position, _, findError := dict.find(intp, key)
+	return position >= 0, findError
+}
+
+// Removes the key and returns its value, or nil if the map does not contain the key.
+func (dict *Map) remove(intp *Interpreter, key any) (any, error) {
+	position, _, findError := dict.find(intp, key)
+	if findError != nil || position < 0 {
+		return nil, findError
+	}
+	removed := dict.entries[position].value
+	dict.entries = append(dict.entries[:position], dict.entries[position+1:]...)
+	dict.positions = make(map[any][]int)
+	for i, entry := range dict.entries {
+		dict.positions[entry.hashed] = append(dict.positions[entry.hashed], i)
+	}
+	return removed, nil
+}
+
+// MARK: - Methods
+
+func (dict *Map) get(name string) (any, error) {
+	switch name {
+	case "keys":
+		return nativeMethod(name, []parameter{}, func(intp *Interpreter, args []any) (any, error) {
+			keys := []any{}
+			for _, entry := range dict.entries {
+				keys = append(keys, entry.key)
+			}
+			return newList(keys), nil
+		}), nil
+	case "values":
+		return nativeMethod(name, []parameter{}, func(intp *Interpreter, args []any) (any, error) {
+			values := []any{}
+			for _, entry := range dict.entries {
+				values = append(values, entry.value)
+			}
+			return newList(values), nil
+		}), nil
+	case "has":
+		return nativeMethod(name, []parameter{{name: "key"}}, func(intp *Interpreter, args []any) (any, error) {
+			return dict.has(intp, args[0])
+		}), nil
+	case "remove":
+		return nativeMethod(name, []parameter{{name: "key"}}, func(intp *Interpreter, args []any) (any, error) {
+			return dict.remove(intp, args[0])
+		}), nil
+	case "len":
+		return nativeMethod(name, []parameter{}, func(intp *Interpreter, args []any) (any, error) {
+			return int64(len(dict.entries)), nil
+		}), nil
+	}
+	return nil, fmt.Errorf("undefined property %v", name)
+}
+
+// MARK: - Iteration
+
+// Iterates over the keys, in insertion order.
+func (dict *Map) iterator() iterator {
+	return &mapIterator{dict: dict}
+}
+
+type mapIterator struct {
+	dict *Map
+	position int
+}
+func (it *mapIterator) next() (any, bool, error) {
+	if it.position >= len(it.dict.entries) {
+		return nil, false, nil
+	}
+	key := it.dict.entries[it.position].key
+	it.position++
+	return key, true, nil
+}
@@ -0,0 +1,290 @@
+package interpreter
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/codecrafters-io/interpreter-starter-go/spec"
+)
+
+// Numbers are int64 for integers, *big.Int for integers that don't fit into an int64, and float64 otherwise.
+// Integer results are always normalized back to int64 when they fit, and any operation that involves a float, as well
+// as division, produces a float.
+
+func isNumber(value any) bool {
+	switch value.(type) {
+	case int64, *big.Int, float64:
+		return true
+	}
+	return false
+}
+
+func isInteger(value any) bool {
+	switch value.(type) {
+	case int64, *big.Int:
+		return true
+	}
+	return false
+}
+
+func toFloat(number any) float64 {
+	switch number := number.(type) {
+	case int64:
+		return float64(number)
+	case *big.Int:
+		float, _ := new(big.Float).SetInt(number).Float64()
+		return float
+	case float64:
+		return number
+	}
+	return math.NaN()
+}
+
+func toBig(integer any) *big.Int {
+	switch integer := integer.(type) {
+	case int64:
+		return big.NewInt(integer)
+	case *big.Int:
+		return integer
+	}
+	return nil
+}
+
+func normalize(integer *big.Int) any {
+	if integer.IsInt64() {
+		return integer.Int64()
+	}
+	return integer
+}
+
+// Converts an integer, or a float without a fractional part, to an int64. Returns false for any other value, or if
+// the number does not fit.
+func toInt64(number any) (int64, bool) {
+	switch number := number.(type) {
+	case int64:
+		return number, true
+	case float64:
+		if number == math.Trunc(number) && number >= math.MinInt64 && number < math.MaxInt64 {
+			return int64(number), true
+		}
+	}
+	return 0, false
+}
+
+// Converts an integer, or a float without a fractional part, to a big.Int. Returns false for any other value.
+func toBigInteger(number any) (*big.Int, bool) {
+	switch number := number.(type) {
+	case int64, *big.Int:
+		return toBig(number), true
+	case float64:
+		if number == math.Trunc(number) && !math.IsInf(number, 0) {
+			integer, _ := big.NewFloat(number).Int(nil)
+			return integer, true
+		}
+	}
+	return nil, false
+}
+
+func isZero(number any) bool {
+	switch number := number.(type) {
+	case int64:
+		return number == 0
+	case float64:
+		return number == 0
+	}
+	return false
+}
+
+// MARK: - Arithmetic
+
+func addNumbers(a any, b any) any {
+	if !isInteger(a) || !isInteger(b) {
+		return toFloat(a) + toFloat(b)
+	}
+	x, xOk := a.(int64)
+	y, yOk := b.(int64)
+	if xOk && yOk && !((y > 0 && x > math.MaxInt64 - y) || (y < 0 && x < math.MinInt64 - y)) {
+		return x + y
+	}
+	return normalize(new(big.Int).Add(toBig(a), toBig(b)))
+}
+
+func subtractNumbers(a any, b any) any {
+	if !isInteger(a) || !isInteger(b) {
+		return toFloat(a) - toFloat(b)
+	}
+	x, xOk := a.(int64)
+	y, yOk := b.(int64)
+	if xOk && yOk && !((y < 0 && x > math.MaxInt64 + y) || (y > 0 && x < math.MinInt64 + y)) {
+		return x - y
+	}
+	return normalize(new(big.Int).Sub(toBig(a), toBig(b)))
+}
+
+func multiplyNumbers(a any, b any) any {
+	if !isInteger(a) || !isInteger(b) {
+		return toFloat(a) * toFloat(b)
+	}
+	x, xOk := a.(int64)
+	y, yOk := b.(int64)
+	if xOk && yOk {
+		product := x * y
+		if x == 0 || (product / x == y && !(x == -1 && y == math.MinInt64) && !(y == -1 && x == math.MinInt64)) {
+			return product
+		}
+	}
+	return normalize(new(big.Int).Mul(toBig(a), toBig(b)))
+}
+
+func divideNumbers(a any, b any) any {
+	return toFloat(a) / toFloat(b)
+}
+
+// Divides and rounds the quotient down. Integers produce an integer, while floats produce a float without a fractional
+// part. The divisor must not be zero.
+func floorDivideNumbers(a any, b any) any {
+	if !isInteger(a) || !isInteger(b) {
+		return math.Floor(toFloat(a) / toFloat(b))
+	}
+	x, xOk := a.(int64)
+	y, yOk := b.(int64)
+	if xOk && yOk && !(x == math.MinInt64 && y == -1) {
+		quotient := x / y
+		if x % y != 0 && (x < 0) != (y < 0) {
+			quotient--
+		}
+		return quotient
+	}
+	quotient, remainder := new(big.Int).QuoRem(toBig(a), toBig(b), new(big.Int))
+	if remainder.Sign() != 0 && remainder.Sign() != toBig(b).Sign() {
+		quotient.Sub(quotient, big.NewInt(1))
+	}
+	return normalize(quotient)
+}
+
+// Computes the remainder of a floored division, which takes the sign of the divisor. The divisor must not be zero.
+func moduloNumbers(a any, b any) any {
+	if !isInteger(a) || !isInteger(b) {
+		x, y := toFloat(a), toFloat(b)
+		remainder := math.Mod(x, y)
+		if remainder != 0 && (remainder < 0) != (y < 0) {
+			remainder += y
+		}
+		return remainder
+	}
+	x, xOk := a.(int64)
+	y, yOk := b.(int64)
+	if xOk && yOk && y != -1 {
+		remainder := x % y
+		if remainder != 0 && (remainder < 0) != (y < 0) {
+			remainder += y
+		}
+		return remainder
+	}
+	remainder := new(big.Int).Rem(toBig(a), toBig(b))
+	if remainder.Sign() != 0 && remainder.Sign() != toBig(b).Sign() {
+		remainder.Add(remainder, toBig(b))
+	}
+	return normalize(remainder)
+}
+
+// Raises a to the power of b. An integer raised to a non-negative integer is exact, anything else produces a float.
+// Fails if an exact result would have more than maxPowerBits bits.
+func powerNumbers(a any, b any) (any, error) {
+	if isInteger(a) && isInteger(b) && toBig(b).Sign() >= 0 {
+		base, exponent := toBig(a), toBig(b)
+		// the result has at least (bits of |a| - 1) * b bits; bases 0, 1 and -1 have no such bound, but stay small
+		minBits := new(big.Int).Mul(big.NewInt(int64(base.BitLen() - 1)), exponent)
+		if minBits.Cmp(big.NewInt(maxPowerBits)) > 0 {
+			return nil, errors.New("Power result too large")
+		}
+		return normalize(new(big.Int).Exp(base, exponent, nil)), nil
+	}
+	return math.Pow(toFloat(a), toFloat(b)), nil
+}
+
+// The most bits an exact integer power may have; the largest such power takes 2 MiB.
+const maxPowerBits = 1 << 24
+
+func negateNumber(a any) any {
+	switch a := a.(type) {
+	case int64:
+		if a != math.MinInt64 {
+			return -a
+		}
+	case float64:
+		return -a
+	}
+	return normalize(new(big.Int).Neg(toBig(a)))
+}
+
+// MARK: - Comparison
+
+// Compares two numbers of any kind exactly, returning -1, 0 or 1. The second value is false if either of them is NaN.
+func compareNumbers(a any, b any) (int, bool) {
+	if isInteger(a) && isInteger(b) {
+		return toBig(a).Cmp(toBig(b)), true
+	}
+	if math.IsNaN(toFloat(a)) || math.IsNaN(toFloat(b)) {
+		return 0, false
+	}
+	return toBigFloat(a).Cmp(toBigFloat(b)), true
+}
+
+func toBigFloat(number any) *big.Float {
+	switch number := number.(type) {
+	case int64:
+		return new(big.Float).SetInt64(number)
+	case *big.Int:
+		return new(big.Float).SetInt(number)
+	case float64:
+		return big.NewFloat(number)
+	}
+	return nil
+}
+
+// MARK: - Bitwise
+
+// Applies a bitwise operator to two integers (or integral floats), producing an integer.
+func bitwiseNumbers(operator spec.TokenType, a any, b any) (any, error) {
+	x, xOk := toBigInteger(a)
+	y, yOk := toBigInteger(b)
+	if !xOk || !yOk {
+		return nil, errors.New("Operands must be integers")
+	}
+	result := new(big.Int)
+	switch operator {
+	case spec.Ampersand:
+		result.And(x, y)
+	case spec.Pipe:
+		result.Or(x, y)
+	case spec.Caret:
+		result.Xor(x, y)
+	case spec.LessLess, spec.GreaterGreater:
+		if y.Sign() < 0 {
+			return nil, errors.New("Negative shift count")
+		}
+		if !y.IsInt64() || y.Int64() > maxShiftCount {
+			return nil, errors.New("Shift count too large")
+		}
+		if operator == spec.LessLess {
+			result.Lsh(x, uint(y.Int64()))
+		} else {
+			result.Rsh(x, uint(y.Int64()))
+		}
+	}
+	return normalize(result), nil
+}
+
+// The largest shift count in either direction. A left shift adds as many bits to the number as it shifts by.
+const maxShiftCount = 1 << 24
+
+// Inverts the bits of an integer (or integral float), which equals -a - 1. Returns false if it's not an integer.
+func invertNumber(a any) (any, bool) {
+	x, ok := toBigInteger(a)
+	if !ok {
+		return nil, false
+	}
+	return normalize(new(big.Int).Not(x)), true
+}
@@ -0,0 +1,131 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/codecrafters-io/interpreter-starter-go/spec"
+)
+
+// Classes overload operators by defining special methods. For a binary operator, the left operand's method is tried
+// first, then the reflected method of the right operand, which receives the left operand as its argument.
+
+// The method of the left operand, and the reflected method of the right operand, for each binary operator.
+var binaryOperatorMethods = map[spec.TokenType][2]string{
+	spec.Plus:           {"__add__", "__radd__"},
+	spec.Minus:          {"__sub__", "__rsub__"},
+	spec.Star:           {"__mul__", "__rmul__"},
+	spec.Slash:          {"__div__", "__rdiv__"},
+	spec.Percent:        {"__mod__", "__rmod__"},
+	spec.TildeSlash:     {"__floordiv__", "__rfloordiv__"},
+	spec.StarStar:       {"__pow__", "__rpow__"},
+	spec.Ampersand:      {"__and__", "__rand__"},
+	spec.Pipe:           {"__or__", "__ror__"},
+	spec.Caret:          {"__xor__", "__rxor__"},
+	spec.LessLess:       {"__lshift__", "__rlshift__"},
+	spec.GreaterGreater: {"__rshift__", "__rrshift__"},
+	// comparisons are reflected by swapping the operands: `a < b` is `b > a`
+	spec.Less:         {"__lt__", "__gt__"},
+	spec.LessEqual:    {"__le__", "__ge__"},
+	spec.Greater:      {"__gt__", "__lt__"},
+	spec.GreaterEqual: {"__ge__", "__le__"},
+	// `!=` negates `__eq__`
+	spec.EqualEqual: {"__eq__", "__eq__"},
+	spec.BangEqual:  {"__eq__", "__eq__"},
+}
+
+var unaryOperatorMethods = map[spec.TokenType]string{
+	spec.Minus: "__neg__",
+	spec.Tilde: "__invert__",
+}
+
+// Applies a binary operator overloaded by an instance operand. Returns false if neither operand overloads it.
+func (intp *Interpreter) overloadBinary(operator spec.Token, left any, right any) (any, bool, error) {
+	names, isOverloadable := binaryOperatorMethods[operator.Type]
+	if !isOverloadable {
+		return nil, false, nil
+	}
+	var result any
+	var isOverloaded bool
+	var err error
+	if inst, ok := left.(*ClassInstance); ok {
+		result, isOverloaded, err = intp.callOperatorMethod(inst, names[0], operator, right)
+	}
+	if inst, ok := right.(*ClassInstance); ok && !isOverloaded {
+		result, isOverloaded, err = intp.callOperatorMethod(inst, names[1], operator, left)
+	}
+	if isOverloaded && err == nil && operator.Type == spec.BangEqual {
+		result = !isTruthy(result)
+	}
+	return result, isOverloaded, err
+}
+
+// Applies a unary operator overloaded by an instance operand. Returns false if it isn't overloaded.
+func (intp *Interpreter) overloadUnary(operator spec.Token, operand any) (any, bool, error) {
+	name, isOverloadable := unaryOperatorMethods[operator.Type]
+	inst, ok := operand.(*ClassInstance)
+	if !isOverloadable || !ok {
+		return nil, false, nil
+	}
+	return intp.callOperatorMethod(inst, name, operator)
+}
+
+// Calls the method that overloads an operator on an instance. Returns false if the instance's class doesn't define it.
+func (intp *Interpreter) callOperatorMethod(
+	inst *ClassInstance, name string, operator spec.Token, args ...any,
+) (any, bool, error) {
+	method, found := inst.Class.findMethod(name)
+	if !found {
+		return nil, false, nil
+	}
+	result, callError := intp.callWith(method.bind(inst), args...)
+	if callError != nil {
+		return nil, true, withLine(callError, operator.Line)
+	}
+	return result, true, nil
+}
+
+func undefinedOperator(operator string, line uint64, operands ...any) runtimeError {
+	names := []any{}
+	for _, operand := range operands {
+		if inst, ok := operand.(*ClassInstance); ok {
+			names = append(names, inst.String())
+		} else {
+			names = append(names, typeName(operand))
+		}
+	}
+	message := fmt.Sprintf("Operator '%v' is not defined for %v", operator, names[0])
+	if len(names) == 2 {
+		message = fmt.Sprintf("Operator '%v' is not defined for %v and %v", operator, names[0], names[1])
+	}
+	return runtimeError{message: message, line: line}
+}
+
+// Checks whether either value is an instance whose class defines toString().
+func hasToString(values ...any) bool {
+	for _, value := range values {
+		if inst, ok := value.(*ClassInstance); ok {
+			if _, found := inst.Class.findMethod("toString"); found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Concatenates the string representations of two values, at least one of which is a string.
+func (intp *Interpreter) concatenate(left any, right any, line uint64) (any, error) {
+	leftString, leftError := intp.stringify(left)
+	if leftError != nil {
+		return nil, withLine(leftError, line)
+	}
+	rightString, rightError := intp.stringify(right)
+	if rightError != nil {
+		return nil, withLine(rightError, line)
+	}
+	return leftString + rightString, nil
+}
+
+func isInstance(value any) bool {
+	_, ok := value.(*ClassInstance)
+	return ok
+}
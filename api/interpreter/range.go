@@ -0,0 +1,100 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// A lazy sequence of integers from start to end, counting by step, which may be negative. The end is only part of
+// the sequence for an inclusive range (`a..b`), and only if the step lands on it.
+type Range struct { // implements iterable
+	start int64
+	end int64
+	step int64
+	inclusive bool
+}
+
+func (r Range) String() string {
+	operator := "..<"
+	if r.inclusive {
+		operator = ".."
+	}
+	if r.step == 1 {
+		return fmt.Sprintf("%v%v%v", r.start, operator, r.end)
+	}
+	return fmt.Sprintf("%v%v%v step %v", r.start, operator, r.end, r.step)
+}
+
+// Checks whether a value is within the range's bounds, without regard to the step.
+func (r Range) isInBounds(value int64) bool {
+	if r.step > 0 {
+		return value >= r.start && (value < r.end || (r.inclusive && value == r.end))
+	}
+	return value <= r.start && (value > r.end || (r.inclusive && value == r.end))
+}
+
+func (r Range) contains(value any) bool {
+	integer, isInt := toInt64(value)
+	if !isInt || !r.isInBounds(integer) {
+		return false
+	}
+	offset := new(big.Int).Sub(big.NewInt(integer), big.NewInt(r.start))
+	return offset.Rem(offset, big.NewInt(r.step)).Sign() == 0
+}
+
+func (r Range) len() any {
+	distance := new(big.Int).Sub(big.NewInt(r.end), big.NewInt(r.start))
+	step := big.NewInt(r.step)
+	if r.step < 0 {
+		distance.Neg(distance)
+		step.Neg(step)
+	}
+	if !r.inclusive {
+		distance.Sub(distance, big.NewInt(1))
+	}
+	if distance.Sign() < 0 {
+		return int64(0)
+	}
+	count := distance.Quo(distance, step)
+	return normalize(count.Add(count, big.NewInt(1)))
+}
+
+func (r Range) get(name string) (any, error) {
+	switch name {
+	case "contains":
+		return nativeMethod(name, []parameter{{name: "value"}}, func(intp *Interpreter, args []any) (any, error) {
+			return r.contains(args[0]), nil
+		}), nil
+	case "len":
+		return nativeMethod(name, []parameter{}, func(intp *Interpreter, args []any) (any, error) {
+			return r.len(), nil
+		}), nil
+	}
+	return nil, fmt.Errorf("undefined property %v", name)
+}
+
+// MARK: - Iteration
+
+func (r Range) iterator() iterator {
+	return &rangeIterator{r: r, current: r.start}
+}
+
+type rangeIterator struct {
+	r Range
+	current int64
+	// set once stepping further would overflow
+	done bool
+}
+func (it *rangeIterator) next() (any, bool, error) {
+	if it.done || !it.r.isInBounds(it.current) {
+		return nil, false, nil
+	}
+	value, step := it.current, it.r.step
+	if (step > 0 && value > math.MaxInt64 - step) || (step < 0 && value < math.MinInt64 - step) {
+		it.done = true
+	} else {
+		it.current += step
+	}
+	return value, true, nil
+}
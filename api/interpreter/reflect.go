@@ -0,0 +1,150 @@
+package interpreter
+
+import (
+	"errors"
+	"sort"
+)
+
+// Natives that let scripts inspect values at runtime, e.g. to serialize any instance. They only see public members:
+// private fields and methods stay hidden, and can't be accessed by name.
+var reflectionFunctions = []*NativeFunction {
+	{
+		_name: "type",
+		_params: []parameter{{name: "value"}},
+		_func: func(intp *Interpreter, args []any) (any, error) {
+			return typeName(args[0]), nil
+		},
+	},
+	{
+		_name: "classOf",
+		_params: []parameter{{name: "value"}},
+		_func: func(intp *Interpreter, args []any) (any, error) {
+			if inst, ok := args[0].(*ClassInstance); ok {
+				return inst.Class, nil
+			}
+			return nil, nil
+		},
+	},
+	{
+		_name: "fields",
+		_params: []parameter{{name: "object"}},
+		_func: func(intp *Interpreter, args []any) (any, error) {
+			inst, ok := args[0].(*ClassInstance)
+			if !ok {
+				return nil, errors.New("Argument must be an instance")
+			}
+			names := []string{}
+			for name := range inst.Fields {
+				names = append(names, name)
+			}
+			return sortedNames(names), nil
+		},
+	},
+	{
+		_name: "methods",
+		_params: []parameter{{name: "classOrObject"}},
+		_func: func(intp *Interpreter, args []any) (any, error) {
+			class, ok := args[0].(*Class)
+			if inst, isInstance := args[0].(*ClassInstance); isInstance {
+				class, ok = inst.Class, true
+			}
+			if !ok {
+				return nil, errors.New("Argument must be a class or an instance")
+			}
+			isListed := make(map[string]bool)
+			names := []string{}
+			for current := class; current != nil; current = current.Superclass {
+				for name := range current.Methods {
+					if !isListed[name] {
+						isListed[name] = true
+						names = append(names, name)
+					}
+				}
+			}
+			return sortedNames(names), nil
+		},
+	},
+	{
+		_name: "hasField",
+		_params: []parameter{{name: "object"}, {name: "name"}},
+		_func: func(intp *Interpreter, args []any) (any, error) {
+			inst, ok := args[0].(*ClassInstance)
+			if !ok {
+				return nil, errors.New("First argument must be an instance")
+			}
+			name, nameError := memberName(args[1])
+			if nameError != nil {
+				return nil, nameError
+			}
+			_, contains := inst.Fields[name]
+			return contains, nil
+		},
+	},
+	{
+		_name: "getField",
+		_params: []parameter{{name: "object"}, {name: "name"}},
+		_func: func(intp *Interpreter, args []any) (any, error) {
+			name, nameError := memberName(args[1])
+			if nameError != nil {
+				return nil, nameError
+			}
+			switch object := args[0].(type) {
+			case *ClassInstance:
+				return object.get(intp, name)
+			case *Class:
+				return object.get(name)
+			}
+			return nil, errors.New("First argument must be an instance or a class")
+		},
+	},
+	{
+		_name: "setField",
+		_params: []parameter{{name: "object"}, {name: "name"}, {name: "value"}},
+		_func: func(intp *Interpreter, args []any) (any, error) {
+			name, nameError := memberName(args[1])
+			if nameError != nil {
+				return nil, nameError
+			}
+			switch object := args[0].(type) {
+			case *ClassInstance:
+				return args[2], object.set(intp, name, args[2])
+			case *Class:
+				object.set(name, args[2])
+				return args[2], nil
+			}
+			return nil, errors.New("First argument must be an instance or a class")
+		},
+	},
+	{
+		// The number of parameters, counting optional parameters and a rest parameter
+		_name: "arity",
+		_params: []parameter{{name: "callable"}},
+		_func: func(intp *Interpreter, args []any) (any, error) {
+			callable, ok := args[0].(Callable)
+			if !ok {
+				return nil, errors.New("Argument must be a function or a class")
+			}
+			return int64(len(callable.parameters())), nil
+		},
+	},
+}
+
+// Checks that a member name passed to a reflection native is a string, and not the name of a private member.
+func memberName(value any) (string, error) {
+	name, ok := value.(string)
+	if !ok {
+		return "", errors.New("Name must be a string")
+	} else if isPrivate(name) {
+		return "", errors.New("Private member accessed outside class")
+	}
+	return name, nil
+}
+
+func sortedNames(names []string) *List {
+	sort.Strings(names)
+	elements := make([]any, len(names))
+	for i, name := range names {
+		elements[i] = name
+	}
+	return newList(elements)
+}
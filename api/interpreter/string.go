@@ -0,0 +1,122 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Strings are plain Go strings. Their indices and lengths count runes rather than bytes, so that every character of a
+// string can be reached by index.
+
+func stringGetAt(str string, index any) (any, error) {
+	runes := []rune(str)
+	i, indexError := toIndex(index, len(runes))
+	if indexError != nil {
+		return nil, indexError
+	}
+	return string(runes[i]), nil
+}
+
+func stringSlice(str string, start any, end any) (any, error) {
+	runes := []rune(str)
+	from, to, boundsError := sliceBounds(start, end, len(runes))
+	if boundsError != nil {
+		return nil, boundsError
+	}
+	return string(runes[from:to]), nil
+}
+
+// MARK: - Methods
+
+func stringGet(str string, name string) (any, error) {
+	switch name {
+	case "len":
+		return nativeMethod(name, []parameter{}, func(intp *Interpreter, args []any) (any, error) {
+			return int64(utf8.RuneCountInString(str)), nil
+		}), nil
+	case "upper":
+		return nativeMethod(name, []parameter{}, func(intp *Interpreter, args []any) (any, error) {
+			return strings.ToUpper(str), nil
+		}), nil
+	case "lower":
+		return nativeMethod(name, []parameter{}, func(intp *Interpreter, args []any) (any, error) {
+			return strings.ToLower(str), nil
+		}), nil
+	case "trim":
+		return nativeMethod(name, []parameter{}, func(intp *Interpreter, args []any) (any, error) {
+			return strings.TrimSpace(str), nil
+		}), nil
+	case "split":
+		params := []parameter{{name: "separator", optional: true}}
+		return nativeMethod(name, params, func(intp *Interpreter, args []any) (any, error) {
+			var parts []string
+			if args[0] == nil {
+				parts = strings.Fields(str)
+			} else if separator, ok := args[0].(string); ok {
+				parts = strings.Split(str, separator)
+			} else {
+				return nil, errors.New("Separator must be a string")
+			}
+			elements := make([]any, len(parts))
+			for i, part := range parts {
+				elements[i] = part
+			}
+			return newList(elements), nil
+		}), nil
+	case "replace":
+		params := []parameter{{name: "old"}, {name: "new"}}
+		return nativeMethod(name, params, func(intp *Interpreter, args []any) (any, error) {
+			old, oldOk := args[0].(string)
+			replacement, replacementOk := args[1].(string)
+			if !oldOk || !replacementOk {
+				return nil, errors.New("Arguments must be strings")
+			}
+			return strings.ReplaceAll(str, old, replacement), nil
+		}), nil
+	case "find":
+		return nativeMethod(name, []parameter{{name: "substring"}}, func(intp *Interpreter, args []any) (any, error) {
+			substring, ok := args[0].(string)
+			if !ok {
+				return nil, errors.New("Argument must be a string")
+			}
+			position := strings.Index(str, substring)
+			if position < 0 {
+				return int64(-1), nil
+			}
+			return int64(utf8.RuneCountInString(str[:position])), nil
+		}), nil
+	case "startsWith":
+		return nativeMethod(name, []parameter{{name: "prefix"}}, func(intp *Interpreter, args []any) (any, error) {
+			prefix, ok := args[0].(string)
+			if !ok {
+				return nil, errors.New("Argument must be a string")
+			}
+			return strings.HasPrefix(str, prefix), nil
+		}), nil
+	case "endsWith":
+		return nativeMethod(name, []parameter{{name: "suffix"}}, func(intp *Interpreter, args []any) (any, error) {
+			suffix, ok := args[0].(string)
+			if !ok {
+				return nil, errors.New("Argument must be a string")
+			}
+			return strings.HasSuffix(str, suffix), nil
+		}), nil
+	case "repeat":
+		return nativeMethod(name, []parameter{{name: "count"}}, func(intp *Interpreter, args []any) (any, error) {
+			count, ok := toInt64(args[0])
+			if !ok || count < 0 {
+				return nil, errors.New("Count must be a non-negative integer")
+			}
+			if len(str) > 0 && count > maxStringLength / int64(len(str)) {
+				return nil, errors.New("Repeated string is too long")
+			}
+			return strings.Repeat(str, int(count)), nil
+		}), nil
+	}
+	return nil, fmt.Errorf("undefined property %v", name)
+}
+
+// The longest string, in bytes, that repeat produces.
+const maxStringLength = 1 << 30
@@ -0,0 +1,61 @@
+package interpreter
+
+import (
+	"fmt"
+)
+
+// An immutable sequence that compares by value.
+type Tuple struct { // implements iterable
+	elements []any
+}
+
+func (tuple Tuple) String() string {
+	return stringify(tuple)
+}
+
+func (tuple Tuple) getAt(index any) (any, error) {
+	i, indexError := toIndex(index, len(tuple.elements))
+	if indexError != nil {
+		return nil, indexError
+	}
+	return tuple.elements[i], nil
+}
+
+func (tuple Tuple) slice(start any, end any) (any, error) {
+	from, to, boundsError := sliceBounds(start, end, len(tuple.elements))
+	if boundsError != nil {
+		return nil, boundsError
+	}
+	return Tuple{elements: tuple.elements[from:to]}, nil
+}
+
+func (tuple Tuple) get(name string) (any, error) {
+	switch name {
+	case "len":
+		return nativeMethod(name, []parameter{}, func(intp *Interpreter, args []any) (any, error) {
+			return int64(len(tuple.elements)), nil
+		}), nil
+	}
+	return nil, fmt.Errorf("undefined property %v", name)
+}
+
+func (tuple Tuple) iterator() iterator {
+	return &listIterator{list: newList(tuple.elements)}
+}
+
+// Splits a tuple or a list into exactly the given number of values, for destructuring.
+func unpack(value any, count int) ([]any, error) {
+	var elements []any
+	switch value := value.(type) {
+	case Tuple:
+		elements = value.elements
+	case *List:
+		elements = value.elements
+	default:
+		return nil, fmt.Errorf("Can only destructure tuples and lists")
+	}
+	if len(elements) != count {
+		return nil, fmt.Errorf("Expected %v values to destructure but got %v", count, len(elements))
+	}
+	return elements, nil
+}
@@ -30,6 +30,8 @@ func ParseStmts(tokens *[]spec.Token) ([]spec.Stmt, error) {
 type parser struct {
 	tokens *[]spec.Token
 	position int
+	// One entry per function body being parsed, recording whether it contains a yield statement.
+	yields stack[bool]
 }
 
 // MARK: - Grammar rules
@@ -40,7 +42,14 @@ func (p *parser) declaration() (spec.Stmt, error) {
 	if (p.match(spec.Class)) {
 		return p.classDesclaration()
 	}
-	if (p.match(spec.Fun)) {
+	if p.match(spec.Trait) {
+		return p.traitDeclaration()
+	}
+	if p.match(spec.Interface) {
+		return p.interfaceDeclaration()
+	}
+	if p.check(spec.Fun) && p.peekNext().Type == spec.Identifier {
+		p.advance()
 		return p.funcDeclaration()
 	}
 	if (p.match(spec.Var)) {
@@ -64,23 +73,243 @@ func (p *parser) classDesclaration() (spec.Stmt, error) {
 		superclass = &spec.VariableExpr{Identifier: superclassIdent, Occurrence: rand.Float64()}
 	}
 
+	traits := []spec.VariableExpr{}
+	if p.match(spec.With) {
+		var traitsError error
+		if traits, traitsError = p.nameList("Expect trait name"); traitsError != nil {
+			return nil, traitsError
+		}
+	}
+	interfaces := []spec.VariableExpr{}
+	if p.match(spec.Implements) {
+		var interfacesError error
+		if interfaces, interfacesError = p.nameList("Expect interface name"); interfacesError != nil {
+			return nil, interfacesError
+		}
+	}
+
 	if _, braceError := p.consume(spec.LeftBrace, "Expect '{' after function name"); braceError != nil {
 		return nil, braceError
 	}
 
-	methods := []spec.FuncStmt{}
+	methods, staticMethods, staticFields := []spec.FuncStmt{}, []spec.FuncStmt{}, []spec.DeclareStmt{}
+	setters, fields := []spec.FuncStmt{}, []spec.DeclareStmt{}
 	for !p.check(spec.RightBrace) && !p.check(spec.EOF) {
-		method, methodError := p.funcDeclaration()
+		isStatic := p.match(spec.Class)
+		if p.match(spec.Var) {
+			field, fieldError := p.fieldDeclaration()
+			if fieldError != nil {
+				return nil, fieldError
+			}
+			if !isStatic {
+				fields = append(fields, field)
+			} else if field.Identifier.Type == spec.PrivateIdentifier {
+				return nil, p.errorAt(field.Identifier, "A static field can't be private")
+			} else {
+				staticFields = append(staticFields, field)
+			}
+			continue
+		} else if isStatic {
+			method, methodError := p.funcDeclaration()
+			if methodError != nil {
+				return nil, methodError
+			}
+			staticMethods = append(staticMethods, method.(spec.FuncStmt))
+			continue
+		}
+		// `set` is only a keyword when it's followed by the property name
+		if p.check(spec.Identifier) && p.peek().Lexeme == "set" && p.peekNext().Type == spec.Identifier {
+			p.advance()
+			setter, setterError := p.setterDeclaration()
+			if setterError != nil {
+				return nil, setterError
+			}
+			setters = append(setters, setter)
+			continue
+		}
+		method, methodError := p.methodDeclaration()
 		if methodError != nil {
 			return nil, methodError
 		}
-		methods = append(methods, method.(spec.FuncStmt))
+		methods = append(methods, method)
 	}
 
 	if _, braceError := p.consume(spec.RightBrace, "Expect '}' after function name"); braceError != nil {
 		return nil, braceError
 	}
-	return spec.ClassStmt{Name: name, Methods: methods, Superclass: superclass}, nil
+	return spec.ClassStmt{
+		Name: name,
+		Methods: methods,
+		Superclass: superclass,
+		Fields: fields,
+		StaticMethods: staticMethods,
+		StaticFields: staticFields,
+		Setters: setters,
+		Traits: traits,
+		Interfaces: interfaces,
+	}, nil
+}
+
+// Parses a comma-separated list of names, e.g. the traits after `with`.
+func (p *parser) nameList(errorMessage string) ([]spec.VariableExpr, error) {
+	names := []spec.VariableExpr{}
+	for {
+		identifier, consumeError := p.consume(spec.Identifier, errorMessage)
+		if consumeError != nil {
+			return nil, consumeError
+		}
+		names = append(names, spec.VariableExpr{Identifier: identifier, Occurrence: rand.Float64()})
+		if !p.match(spec.Comma) {
+			return names, nil
+		}
+	}
+}
+
+func (p *parser) traitDeclaration() (spec.Stmt, error) {
+	name, nameError := p.consume(spec.Identifier, "Expect trait name")
+	if nameError != nil {
+		return nil, nameError
+	}
+	if _, braceError := p.consume(spec.LeftBrace, "Expect '{' before trait body"); braceError != nil {
+		return nil, braceError
+	}
+	methods := []spec.FuncStmt{}
+	for !p.check(spec.RightBrace) && !p.check(spec.EOF) {
+		method, methodError := p.methodDeclaration()
+		if methodError != nil {
+			return nil, methodError
+		}
+		methods = append(methods, method)
+	}
+	if _, braceError := p.consume(spec.RightBrace, "Expect '}' after trait body"); braceError != nil {
+		return nil, braceError
+	}
+	return spec.TraitStmt{Name: name, Methods: methods}, nil
+}
+
+// Parses a method, or a getter if the name is directly followed by the body. A method whose name starts with '#' is
+// private.
+func (p *parser) methodDeclaration() (spec.FuncStmt, error) {
+	if !p.check(spec.Identifier) && !p.check(spec.PrivateIdentifier) {
+		return spec.FuncStmt{}, p.errorAt(p.peek(), "Expect method name")
+	}
+	name := p.advance()
+	if p.match(spec.LeftBrace) {
+		if name.Lexeme == "init" {
+			return spec.FuncStmt{}, p.errorAt(name, "An initializer can't be a getter")
+		}
+		body, isGenerator, bodyError := p.functionBody()
+		if bodyError != nil {
+			return spec.FuncStmt{}, bodyError
+		}
+		return spec.FuncStmt{Name: name, Params: []spec.Param{}, Body: body, IsGenerator: isGenerator, IsGetter: true}, nil
+	}
+	if _, parenError := p.consume(spec.LeftParen, "Expect '(' or '{' after method name"); parenError != nil {
+		return spec.FuncStmt{}, parenError
+	}
+	if p.isAbstractMethod() {
+		if name.Lexeme == "init" {
+			return spec.FuncStmt{}, p.errorAt(name, "An initializer can't be abstract")
+		}
+		return p.abstractMethodRemainder(name, "Expect ';' after abstract method")
+	}
+	return p.funcRemainder(name)
+}
+
+// Checks whether the parameter list ahead (after the opening parenthesis) is followed by ';' rather than a body.
+func (p *parser) isAbstractMethod() bool {
+	depth := 1
+	for offset := 0; ; offset++ {
+		switch p.peekAt(offset).Type {
+		case spec.LeftParen:
+			depth++
+		case spec.RightParen:
+			depth--
+			if depth == 0 {
+				return p.peekAt(offset + 1).Type == spec.Semicolon
+			}
+		case spec.EOF:
+			return false
+		}
+	}
+}
+
+// Parses the parameter list (after the opening parenthesis) and the ';' of a method without a body, i.e. an abstract
+// method or a method of an interface.
+func (p *parser) abstractMethodRemainder(name spec.Token, semicolonMessage string) (spec.FuncStmt, error) {
+	params, paramsError := p.parameters()
+	if paramsError != nil {
+		return spec.FuncStmt{}, paramsError
+	}
+	if _, semicolonError := p.consume(spec.Semicolon, semicolonMessage); semicolonError != nil {
+		return spec.FuncStmt{}, semicolonError
+	}
+	return spec.FuncStmt{Name: name, Params: params, Body: []spec.Stmt{}, IsAbstract: true}, nil
+}
+
+func (p *parser) interfaceDeclaration() (spec.Stmt, error) {
+	name, nameError := p.consume(spec.Identifier, "Expect interface name")
+	if nameError != nil {
+		return nil, nameError
+	}
+	if _, braceError := p.consume(spec.LeftBrace, "Expect '{' before interface body"); braceError != nil {
+		return nil, braceError
+	}
+	methods := []spec.FuncStmt{}
+	for !p.check(spec.RightBrace) && !p.check(spec.EOF) {
+		methodName, methodError := p.consume(spec.Identifier, "Expect method name")
+		if methodError != nil {
+			return nil, methodError
+		}
+		if _, parenError := p.consume(spec.LeftParen, "Expect '(' after method name"); parenError != nil {
+			return nil, parenError
+		}
+		method, methodError := p.abstractMethodRemainder(methodName, "Expect ';' after interface method")
+		if methodError != nil {
+			return nil, methodError
+		}
+		methods = append(methods, method)
+	}
+	if _, braceError := p.consume(spec.RightBrace, "Expect '}' after interface body"); braceError != nil {
+		return nil, braceError
+	}
+	return spec.InterfaceStmt{Name: name, Methods: methods}, nil
+}
+
+// Parses a setter, after `set`.
+func (p *parser) setterDeclaration() (spec.FuncStmt, error) {
+	name := p.advance()
+	if _, parenError := p.consume(spec.LeftParen, "Expect '(' after setter name"); parenError != nil {
+		return spec.FuncStmt{}, parenError
+	}
+	setter, setterError := p.funcRemainder(name)
+	if setterError != nil {
+		return spec.FuncStmt{}, setterError
+	}
+	if len(setter.Params) != 1 || setter.Params[0].Default != nil || setter.Params[0].IsRest {
+		return spec.FuncStmt{}, p.errorAt(name, "A setter must have exactly one required parameter")
+	}
+	return setter, nil
+}
+
+// Parses a field declared in a class body, after `var`. A field whose name starts with '#' is private.
+func (p *parser) fieldDeclaration() (spec.DeclareStmt, error) {
+	if !p.check(spec.Identifier) && !p.check(spec.PrivateIdentifier) {
+		return spec.DeclareStmt{}, p.errorAt(p.peek(), "Expect field name")
+	}
+	identifier := p.advance()
+	var expr spec.Expr = spec.LiteralExpr{Value: nil}
+	if p.match(spec.Equal) {
+		if expression, err := p.expression(); err == nil {
+			expr = expression
+		} else {
+			return spec.DeclareStmt{}, err
+		}
+	}
+	if _, err := p.consume(spec.Semicolon, "Expect ';' after field declaration"); err != nil {
+		return spec.DeclareStmt{}, err
+	}
+	return spec.DeclareStmt{Identifier: identifier, Expr: expr}, nil
 }
 
 func (p *parser) funcDeclaration() (spec.Stmt, error) {
@@ -91,15 +320,62 @@ func (p *parser) funcDeclaration() (spec.Stmt, error) {
 	if _, parenError := p.consume(spec.LeftParen, "Expect '(' after function name"); parenError != nil {
 		return nil, parenError
 	}
-	params := []spec.Token{}
+	return p.funcRemainder(name)
+}
+
+// Parses the parameter list (after the opening parenthesis) and the body of a function.
+func (p *parser) funcRemainder(name spec.Token) (spec.FuncStmt, error) {
+	params, paramsError := p.parameters()
+	if paramsError != nil {
+		return spec.FuncStmt{}, paramsError
+	}
+	if _, braceError := p.consume(spec.LeftBrace, "Expect '{' before body"); braceError != nil {
+		return spec.FuncStmt{}, braceError
+	}
+	body, isGenerator, bodyError := p.functionBody()
+	if bodyError != nil {
+		return spec.FuncStmt{}, bodyError
+	}
+	return spec.FuncStmt{Name: name, Params: params, Body: body, IsGenerator: isGenerator}, nil
+}
+
+// Parses a function body (after the opening brace), and reports whether it contains a yield statement.
+func (p *parser) functionBody() ([]spec.Stmt, bool, error) {
+	p.yields.push(false)
+	body, bodyError := p.blockStatement()
+	isGenerator := p.yields.pop()
+	if bodyError != nil {
+		return nil, false, bodyError
+	}
+	return body.(spec.BlockStmt).Statements, isGenerator, nil
+}
+
+// Parses a parameter list, including the closing parenthesis.
+func (p *parser) parameters() ([]spec.Param, error) {
+	params := []spec.Param{}
 	if !p.check(spec.RightParen) {
 		for next := true; next; next = p.match(spec.Comma) {
 			if len(params) >= 255 {
 				return nil, fmt.Errorf("can't have more than 255 parameters")
 			}
-			param, paramError := p.consume(spec.Identifier, "Expect parameter name")
-			if paramError != nil {
-				return nil, paramError
+			isRest := p.match(spec.DotDotDot)
+			name, nameError := p.consume(spec.Identifier, "Expect parameter name")
+			if nameError != nil {
+				return nil, nameError
+			}
+			param := spec.Param{Name: name, IsRest: isRest}
+			if isRest {
+				if !p.check(spec.RightParen) {
+					return nil, p.errorAt(p.peek(), "Rest parameter must be the last parameter")
+				}
+			} else if p.match(spec.Equal) {
+				defaultValue, defaultError := p.expression()
+				if defaultError != nil {
+					return nil, defaultError
+				}
+				param.Default = defaultValue
+			} else if len(params) > 0 && params[len(params) - 1].Default != nil {
+				return nil, p.errorAt(name, "Parameter without default can't follow a parameter with a default")
 			}
 			params = append(params, param)
 		}
@@ -107,17 +383,13 @@ func (p *parser) funcDeclaration() (spec.Stmt, error) {
 	if _, parenError := p.consume(spec.RightParen, "Expect ')' after parameters"); parenError != nil {
 		return nil, parenError
 	}
-	if _, braceError := p.consume(spec.LeftBrace, "Expect '{' before body"); braceError != nil {
-		return nil, braceError
-	}
-	body, bodyError := p.blockStatement()
-	if bodyError != nil {
-		return nil, bodyError
-	}
-	return spec.FuncStmt{Name: name, Params: params, Body: body.(spec.BlockStmt).Statements}, nil
+	return params, nil
 }
 
 func (p *parser) varDeclaration() (spec.Stmt, error) {
+	if p.match(spec.LeftParen) {
+		return p.tupleDeclaration()
+	}
 	identifier, consumeError := p.consume(spec.Identifier, "Expect variable name")
 	if consumeError != nil { return nil, consumeError }
 	var expr spec.Expr = spec.LiteralExpr{Value: nil}
@@ -134,10 +406,37 @@ func (p *parser) varDeclaration() (spec.Stmt, error) {
 	return spec.DeclareStmt{Identifier: identifier, Expr: expr}, nil
 }
 
+func (p *parser) tupleDeclaration() (spec.Stmt, error) {
+	paren := p.previous()
+	identifiers := []spec.Token{}
+	for next := true; next; next = p.match(spec.Comma) {
+		identifier, identError := p.consume(spec.Identifier, "Expect variable name")
+		if identError != nil {
+			return nil, identError
+		}
+		identifiers = append(identifiers, identifier)
+	}
+	if _, err := p.consume(spec.RightParen, "Expect ')' after variable names"); err != nil {
+		return nil, err
+	}
+	if _, err := p.consume(spec.Equal, "Expect '=' after variable names"); err != nil {
+		return nil, err
+	}
+	expr, exprError := p.expression()
+	if exprError != nil {
+		return nil, exprError
+	}
+	if _, err := p.consume(spec.Semicolon, "Expect ';' after variable declaration"); err != nil {
+		return nil, err
+	}
+	return spec.DeclareTupleStmt{Paren: paren, Identifiers: identifiers, Expr: expr}, nil
+}
+
 func (p *parser) statement() (spec.Stmt, error) {
 	if p.match(spec.Print) {
 		return p.printStatement()
-	} else if p.match(spec.LeftBrace) {
+	} else if p.check(spec.LeftBrace) && !p.isMapLiteralAhead() {
+		p.advance()
 		return p.blockStatement()
 	} else if p.match(spec.If) {
 		return p.ifStatement()
@@ -147,6 +446,8 @@ func (p *parser) statement() (spec.Stmt, error) {
 		return p.forStatement()
 	} else if p.match(spec.Return) {
 		return p.returnStatement()
+	} else if p.match(spec.Yield) {
+		return p.yieldStatement()
 	}
 	return p.expressionStatement()
 }
@@ -163,12 +464,13 @@ func (p *parser) expressionStatement() (spec.Stmt, error) {
 }
 
 func (p *parser) printStatement() (spec.Stmt, error) {
+	keyword := p.previous()
 	expr, err := p.expression()
 	if err != nil { return nil, err }
 	if _, err := p.consume(spec.Semicolon, "Expect ';' after value"); err != nil {
 		return nil, err
 	}
-	return spec.PrintStmt{Expr: expr}, nil
+	return spec.PrintStmt{Keyword: keyword, Expr: expr}, nil
 }
 
 func (p *parser) blockStatement() (spec.Stmt, error) {
@@ -239,6 +541,9 @@ func (p *parser) forStatement() (spec.Stmt, error) {
 	if _, err := p.consume(spec.LeftParen, "Expect '(' after 'for'."); err != nil {
 		return nil, err
 	}
+	if p.check(spec.Var) && p.peekAt(2).Type == spec.In {
+		return p.forInStatement()
+	}
 	// head - initializer
 	var init spec.Stmt
 	if p.match(spec.Semicolon) {
@@ -285,6 +590,47 @@ func (p *parser) forStatement() (spec.Stmt, error) {
 	return forLoopAsStatement(init, cond, incr, body), nil
 }
 
+func (p *parser) forInStatement() (spec.Stmt, error) {
+	p.advance()
+	identifier, identError := p.consume(spec.Identifier, "Expect variable name")
+	if identError != nil {
+		return nil, identError
+	}
+	keyword := p.advance()
+	iterable, iterableError := p.expression()
+	if iterableError != nil {
+		return nil, iterableError
+	}
+	if _, err := p.consume(spec.RightParen, "Expect ')' after for clauses."); err != nil {
+		return nil, err
+	}
+	body, bodyError := p.statement()
+	if bodyError != nil {
+		return nil, bodyError
+	}
+	return spec.ForInStmt{Identifier: identifier, Keyword: keyword, Iterable: iterable, Body: body}, nil
+}
+
+func (p *parser) yieldStatement() (spec.Stmt, error) {
+	keyword := p.previous()
+	var expr spec.Expr = spec.LiteralExpr{Value: nil}
+	if !p.check(spec.Semicolon) {
+		yieldExpr, yieldError := p.expression()
+		if yieldError != nil {
+			return nil, yieldError
+		}
+		expr = yieldExpr
+	}
+	if _, scError := p.consume(spec.Semicolon, "Expect ';' after yield value"); scError != nil {
+		return nil, scError
+	}
+	if !p.yields.isEmpty() {
+		p.yields.pop()
+		p.yields.push(true)
+	}
+	return spec.YieldStmt{Keyword: keyword, Expr: expr}, nil
+}
+
 func (p *parser) returnStatement() (spec.Stmt, error) {
 	var keyword spec.Token = p.previous()
 	var expr spec.Expr = nil;
@@ -308,11 +654,12 @@ func (p *parser) expression() (spec.Expr, error) {
 }
 
 func (p *parser) assignment() (spec.Expr, error) {
-	expr, exprError := p.or()
+	expr, exprError := p.coalesce()
 	if exprError != nil {
 		return nil, exprError
 	}
 	if p.match(spec.Equal) {
+		equals := p.previous()
 		value, valueError := p.assignment()
 		if valueError != nil {
 			return nil, valueError
@@ -323,6 +670,49 @@ func (p *parser) assignment() (spec.Expr, error) {
 		} else if areTypesEqual(expr, spec.GetExpr{}) {
 			get := expr.(spec.GetExpr)
 			return spec.SetExpr{Object: get.Object, Name: get.Name, Value: value}, nil
+		} else if areTypesEqual(expr, spec.SuperExpr{}) {
+			// `super.name = value` calls the superclass setter, if there is one
+			super := expr.(spec.SuperExpr)
+			return spec.SetExpr{Object: super, Name: super.Method, Value: value}, nil
+		} else if areTypesEqual(expr, spec.IndexGetExpr{}) {
+			get := expr.(spec.IndexGetExpr)
+			return spec.IndexSetExpr{Object: get.Object, Bracket: get.Bracket, Index: get.Index, Value: value}, nil
+		} else if areTypesEqual(expr, spec.TupleExpr{}) {
+			tuple := expr.(spec.TupleExpr)
+			targets := []spec.VariableExpr{}
+			for _, element := range tuple.Elements {
+				target, isVariable := element.(spec.VariableExpr)
+				if !isVariable {
+					return nil, p.errorAt(tuple.Paren, "Invalid assignment target")
+				}
+				targets = append(targets, target)
+			}
+			return spec.TupleAssignmentExpr{Paren: tuple.Paren, Targets: targets, Expr: value}, nil
+		} else if areTypesEqual(expr, spec.OptionalChainExpr{}) {
+			// `a?.b = value` would have to skip the assignment when `a` is nil
+			switch target := expr.(spec.OptionalChainExpr).Expr.(type) {
+			case spec.OptionalGetExpr:
+				return nil, p.errorAt(target.Name, "Invalid assignment target")
+			case spec.GetExpr:
+				return nil, p.errorAt(target.Name, "Invalid assignment target")
+			}
+			return nil, p.errorAt(equals, "Invalid assignment target")
+		}
+	}
+	return expr, nil
+}
+
+func (p *parser) coalesce() (spec.Expr, error) {
+	expr, exprError := p.or()
+	if exprError != nil {
+		return nil, exprError
+	}
+	for p.match(spec.QuestionQuestion) {
+		operator := p.previous()
+		if rightExpr, err := p.or(); err == nil {
+			expr = spec.CoalesceExpr{Left: expr, Opt: operator, Right: rightExpr}
+		} else {
+			return nil, err
 		}
 	}
 	return expr, nil
@@ -377,46 +767,79 @@ func (p *parser) equality() (spec.Expr, error) {
 }
 
 func (p *parser) comparison() (spec.Expr, error) {
-	expr, exprError := p.term()
+	return p.binaryLeftAssoc(p.rangeExpr, spec.Less, spec.LessEqual, spec.Greater, spec.GreaterEqual, spec.Is)
+}
+
+// Ranges don't chain, and their step follows the contextual keyword `step`, which is an identifier anywhere else.
+func (p *parser) rangeExpr() (spec.Expr, error) {
+	expr, exprError := p.bitwiseOr()
 	if exprError != nil {
 		return nil, exprError
 	}
-	for p.match(spec.Less, spec.LessEqual, spec.Greater, spec.GreaterEqual) {
-		operator := p.previous()
-		if rightExpr, err := p.term(); err == nil {
-			expr = spec.BinaryExpr{Left: expr, Opt: operator, Right: rightExpr}
-		} else {
-			return nil, err
+	if !p.match(spec.DotDot, spec.DotDotLess) {
+		return expr, nil
+	}
+	operator := p.previous()
+	end, endError := p.bitwiseOr()
+	if endError != nil {
+		return nil, endError
+	}
+	var step spec.Expr
+	if p.check(spec.Identifier) && p.peek().Lexeme == "step" {
+		p.advance()
+		if step, endError = p.bitwiseOr(); endError != nil {
+			return nil, endError
 		}
 	}
-	return expr, nil
+	return spec.RangeExpr{Start: expr, Opt: operator, End: end, Step: step}, nil
+}
+
+func (p *parser) bitwiseOr() (spec.Expr, error) {
+	return p.binaryLeftAssoc(p.bitwiseXor, spec.Pipe)
+}
+
+func (p *parser) bitwiseXor() (spec.Expr, error) {
+	return p.binaryLeftAssoc(p.bitwiseAnd, spec.Caret)
+}
+
+func (p *parser) bitwiseAnd() (spec.Expr, error) {
+	return p.binaryLeftAssoc(p.shift, spec.Ampersand)
+}
+
+func (p *parser) shift() (spec.Expr, error) {
+	return p.binaryLeftAssoc(p.term, spec.LessLess, spec.GreaterGreater)
 }
 
 func (p *parser) term() (spec.Expr, error) {
-	expr, exprError := p.factor()
-	if exprError != nil {
-		return nil, exprError
-	}
-	for p.match(spec.Plus, spec.Minus) {
+	return p.binaryLeftAssoc(p.factor, spec.Plus, spec.Minus)
+}
+
+func (p *parser) factor() (spec.Expr, error) {
+	return p.binaryLeftAssoc(p.unary, spec.Slash, spec.Star, spec.Percent, spec.TildeSlash)
+}
+
+func (p *parser) unary() (spec.Expr, error) {
+	if p.match(spec.Bang, spec.Minus, spec.Tilde) {
 		operator := p.previous()
-		if rightExpr, err := p.factor(); err == nil {
-			expr = spec.BinaryExpr{Left: expr, Opt: operator, Right: rightExpr}
+		if expr, err := p.unary(); err == nil {
+			return spec.UnaryExpr{Opt: operator, Expr: expr}, nil
 		} else {
 			return nil, err
 		}
 	}
-	return expr, nil
+	return p.power()
 }
 
-func (p *parser) factor() (spec.Expr, error) {
-	expr, exprError := p.unary()
+// Exponentiation binds tighter than a unary operator on its left (`-2 ** 2` is -4), and is right-associative.
+func (p *parser) power() (spec.Expr, error) {
+	expr, exprError := p.call()
 	if exprError != nil {
 		return nil, exprError
 	}
-	for p.match(spec.Slash, spec.Star) {
+	if p.match(spec.StarStar) {
 		operator := p.previous()
 		if rightExpr, err := p.unary(); err == nil {
-			expr = spec.BinaryExpr{Left: expr, Opt: operator, Right: rightExpr}
+			return spec.BinaryExpr{Left: expr, Opt: operator, Right: rightExpr}, nil
 		} else {
 			return nil, err
 		}
@@ -424,16 +847,21 @@ func (p *parser) factor() (spec.Expr, error) {
 	return expr, nil
 }
 
-func (p *parser) unary() (spec.Expr, error) {
-	if p.match(spec.Bang, spec.Minus) {
+// Parses a left-associative sequence of binary operators of the same precedence, with operands parsed by next.
+func (p *parser) binaryLeftAssoc(next func() (spec.Expr, error), operators ...spec.TokenType) (spec.Expr, error) {
+	expr, exprError := next()
+	if exprError != nil {
+		return nil, exprError
+	}
+	for p.match(operators...) {
 		operator := p.previous()
-		if expr, err := p.unary(); err == nil {
-			return spec.UnaryExpr{Opt: operator, Expr: expr}, nil
+		if rightExpr, err := next(); err == nil {
+			expr = spec.BinaryExpr{Left: expr, Opt: operator, Right: rightExpr}
 		} else {
 			return nil, err
 		}
 	}
-	return p.call()
+	return expr, nil
 }
 
 func (p *parser) call() (spec.Expr, error) {
@@ -441,6 +869,7 @@ func (p *parser) call() (spec.Expr, error) {
 	if exprError != nil {
 		return nil, exprError
 	}
+	isOptionalChain := false
 	for {
 		if p.match(spec.LeftParen) {
 			finishedCall, finishedCallError := p.finishCall(expr)
@@ -449,25 +878,55 @@ func (p *parser) call() (spec.Expr, error) {
 			}
 			expr = finishedCall
 		} else if p.match(spec.Dot) {
-			name, nameError := p.consume(spec.Identifier, "Expect property name after '.'")
+			if !p.check(spec.Identifier) && !p.check(spec.PrivateIdentifier) {
+				return nil, p.errorAt(p.peek(), "Expect property name after '.'")
+			}
+			name := p.advance()
+			expr = spec.GetExpr{Object: expr, Name: name} 
+		} else if p.match(spec.LeftBracket) {
+			subscript, subscriptError := p.finishSubscript(expr)
+			if subscriptError != nil {
+				return nil, subscriptError
+			}
+			expr = subscript
+		} else if p.match(spec.QuestionDot) {
+			name, nameError := p.consume(spec.Identifier, "Expect property name after '?.'")
 			if nameError != nil {
 				return nil, nameError
 			}
-			expr = spec.GetExpr{Object: expr, Name: name} 
+			expr = spec.OptionalGetExpr{Object: expr, Name: name}
+			isOptionalChain = true
 		} else {
 			break
 		}
 	}
+	if isOptionalChain {
+		return spec.OptionalChainExpr{Expr: expr}, nil
+	}
 	return expr, nil
 }
 
 func (p *parser) finishCall(callee spec.Expr) (spec.Expr, error) {
 	args := []spec.Expr{};
+	namedArgs := []spec.NamedArg{}
 	if !p.check(spec.RightParen) {
 		for next := true; next; next = p.match(spec.Comma) {
-			if len(args) >= 255 {
+			if len(args) + len(namedArgs) >= 255 {
 				return nil, fmt.Errorf("can't have more than 255 arguments")
 			}
+			if p.check(spec.Identifier) && p.peekNext().Type == spec.Colon {
+				name := p.advance()
+				p.advance()
+				value, valueError := p.expression()
+				if valueError != nil {
+					return nil, valueError
+				}
+				namedArgs = append(namedArgs, spec.NamedArg{Name: name, Value: value})
+				continue
+			}
+			if len(namedArgs) > 0 {
+				return nil, p.errorAt(p.peek(), "Positional arguments must come before named arguments")
+			}
 			arg, argError := p.expression()
 			if argError != nil {
 				return nil, argError
@@ -479,7 +938,38 @@ func (p *parser) finishCall(callee spec.Expr) (spec.Expr, error) {
 	if parenError != nil {
 		return nil, parenError
 	}
-	return spec.CallExpr{Callee: callee, Paren: paren, Args: args}, nil
+	return spec.CallExpr{Callee: callee, Paren: paren, Args: args, NamedArgs: namedArgs}, nil
+}
+
+// Parses an index `[index]` or a slice `[start:end]` (after the opening bracket).
+func (p *parser) finishSubscript(object spec.Expr) (spec.Expr, error) {
+	bracket := p.previous()
+	var start spec.Expr
+	if !p.check(spec.Colon) {
+		index, indexError := p.expression()
+		if indexError != nil {
+			return nil, indexError
+		}
+		start = index
+	}
+	if !p.match(spec.Colon) {
+		if _, err := p.consume(spec.RightBracket, "Expect ']' after index"); err != nil {
+			return nil, err
+		}
+		return spec.IndexGetExpr{Object: object, Bracket: bracket, Index: start}, nil
+	}
+	var end spec.Expr
+	if !p.check(spec.RightBracket) {
+		endExpr, endError := p.expression()
+		if endError != nil {
+			return nil, endError
+		}
+		end = endExpr
+	}
+	if _, err := p.consume(spec.RightBracket, "Expect ']' after slice"); err != nil {
+		return nil, err
+	}
+	return spec.SliceExpr{Object: object, Bracket: bracket, Start: start, End: end}, nil
 }
 
 func (p *parser) primary() (spec.Expr, error) {
@@ -493,17 +983,42 @@ func (p *parser) primary() (spec.Expr, error) {
 		return spec.LiteralExpr{Value: nil}, nil
 	} else if p.match(spec.Number, spec.String) {
 		return spec.LiteralExpr{Value: p.previous().Literal}, nil
+	} else if p.match(spec.Interpolation) {
+		return p.interpolation()
+	} else if p.match(spec.Fun) {
+		keyword := p.previous()
+		if _, parenError := p.consume(spec.LeftParen, "Expect '(' after 'fun'"); parenError != nil {
+			return nil, parenError
+		}
+		declaration, declarationError := p.funcRemainder(keyword)
+		if declarationError != nil {
+			return nil, declarationError
+		}
+		return spec.LambdaExpr{Declaration: declaration}, nil
+	} else if p.check(spec.LeftParen) && p.isArrowFunctionAhead() {
+		return p.arrowFunction()
 	} else if p.match(spec.LeftParen) {
+		paren := p.previous()
+		if p.match(spec.RightParen) {
+			return spec.TupleExpr{Paren: paren, Elements: []spec.Expr{}}, nil
+		}
 		expr, exprError := p.expression()
 		if exprError != nil {
 			return nil, exprError
 		}
+		if p.match(spec.Comma) {
+			return p.finishTuple(paren, expr)
+		}
 		if _, err := p.consume(spec.RightParen, "Expect ')'"); err != nil {
 			return nil, err
 		}
 		return spec.GroupingExpr{Expr: expr}, nil
+	} else if p.match(spec.LeftBracket) {
+		return p.listLiteral()
+	} else if p.match(spec.LeftBrace) {
+		return p.mapLiteral()
 	} else if p.match(spec.This) {
-		return spec.ThisExpr{Keyword: p.previous()}, nil
+		return spec.ThisExpr{Keyword: p.previous(), Occurrence: rand.Float64()}, nil
 	} else if p.match(spec.Super) {
 		keyword := p.previous()
 		if _, err := p.consume(spec.Dot, "Expect '.' after 'super'"); err != nil {
@@ -513,12 +1028,131 @@ func (p *parser) primary() (spec.Expr, error) {
 		if methodErr != nil {
 			return nil, methodErr
 		}
-		return spec.SuperExpr{Keyword: keyword, Method: method}, nil
+		return spec.SuperExpr{Keyword: keyword, Method: method, Occurrence: rand.Float64()}, nil
 	}
 	message := fmt.Sprintf("[line %d] Error at '%v': Expect expression.", p.peek().Line, p.peek().Lexeme)
 	return nil, errors.New(message)
 }
 
+// Parses the rest of a tuple after its first element and comma. A trailing comma is allowed, so `(a,)` is a tuple
+// with one element.
+func (p *parser) finishTuple(paren spec.Token, first spec.Expr) (spec.Expr, error) {
+	elements := []spec.Expr{first}
+	for !p.check(spec.RightParen) {
+		element, elementError := p.expression()
+		if elementError != nil {
+			return nil, elementError
+		}
+		elements = append(elements, element)
+		if !p.match(spec.Comma) {
+			break
+		}
+	}
+	if _, err := p.consume(spec.RightParen, "Expect ')' after tuple elements"); err != nil {
+		return nil, err
+	}
+	return spec.TupleExpr{Paren: paren, Elements: elements}, nil
+}
+
+// Parses an interpolated string, after its first part. The tokenizer emits an Interpolation token for each part that
+// precedes an interpolated expression, and a String token for the part after the last one.
+func (p *parser) interpolation() (spec.Expr, error) {
+	quote := p.previous()
+	parts := []spec.Expr{}
+	for {
+		if text := p.previous().Literal.(string); text != "" {
+			parts = append(parts, spec.LiteralExpr{Value: text})
+		}
+		if p.previous().Type == spec.String {
+			break
+		}
+		expr, exprError := p.expression()
+		if exprError != nil {
+			return nil, exprError
+		}
+		parts = append(parts, expr)
+		if !p.match(spec.Interpolation, spec.String) {
+			return nil, p.errorAt(p.peek(), "Expect '}' after interpolated expression")
+		}
+	}
+	return spec.InterpolationExpr{Quote: quote, Parts: parts}, nil
+}
+
+func (p *parser) listLiteral() (spec.Expr, error) {
+	bracket := p.previous()
+	elements := []spec.Expr{}
+	for !p.check(spec.RightBracket) {
+		element, elementError := p.expression()
+		if elementError != nil {
+			return nil, elementError
+		}
+		elements = append(elements, element)
+		if !p.match(spec.Comma) {
+			break
+		}
+	}
+	if _, err := p.consume(spec.RightBracket, "Expect ']' after list elements"); err != nil {
+		return nil, err
+	}
+	return spec.ListExpr{Bracket: bracket, Elements: elements}, nil
+}
+
+func (p *parser) mapLiteral() (spec.Expr, error) {
+	brace := p.previous()
+	keys, values := []spec.Expr{}, []spec.Expr{}
+	for !p.check(spec.RightBrace) {
+		key, keyError := p.expression()
+		if keyError != nil {
+			return nil, keyError
+		}
+		if _, err := p.consume(spec.Colon, "Expect ':' after map key"); err != nil {
+			return nil, err
+		}
+		value, valueError := p.expression()
+		if valueError != nil {
+			return nil, valueError
+		}
+		keys, values = append(keys, key), append(values, value)
+		if !p.match(spec.Comma) {
+			break
+		}
+	}
+	if _, err := p.consume(spec.RightBrace, "Expect '}' after map entries"); err != nil {
+		return nil, err
+	}
+	return spec.MapExpr{Brace: brace, Keys: keys, Values: values}, nil
+}
+
+// Parses `(params) => expr` or `(params) => { body }`.
+func (p *parser) arrowFunction() (spec.Expr, error) {
+	p.advance()
+	params, paramsError := p.parameters()
+	if paramsError != nil {
+		return nil, paramsError
+	}
+	arrow, arrowError := p.consume(spec.Arrow, "Expect '=>' after parameters")
+	if arrowError != nil {
+		return nil, arrowError
+	}
+	var body []spec.Stmt
+	isGenerator := false
+	if p.match(spec.LeftBrace) {
+		block, isGen, blockError := p.functionBody()
+		if blockError != nil {
+			return nil, blockError
+		}
+		body, isGenerator = block, isGen
+	} else {
+		expr, exprError := p.expression()
+		if exprError != nil {
+			return nil, exprError
+		}
+		body = []spec.Stmt{spec.ReturnStmt{Keyword: arrow, Expr: expr}}
+	}
+	declaration := spec.FuncStmt{Name: arrow, Params: params, Body: body, IsGenerator: isGenerator}
+	return spec.LambdaExpr{Declaration: declaration}, nil
+}
+
 // MARK: - Transformers
 
 func forLoopAsStatement(init spec.Stmt, cond spec.Expr, incr spec.Expr, body spec.Stmt) spec.Stmt {
@@ -574,14 +1208,62 @@ func (p *parser) consume(tokenType spec.TokenType, errorMessage string) (spec.To
 	if p.check(tokenType) {
 		return p.advance(), nil
 	}
-	message := fmt.Sprintf("[line %d] Error at '%v': %s.", p.peek().Line, p.peek().Lexeme, errorMessage)
-	return spec.Token{}, errors.New(message)
+	return spec.Token{}, p.errorAt(p.peek(), errorMessage)
+}
+
+func (p *parser) errorAt(token spec.Token, errorMessage string) error {
+	message := fmt.Sprintf("[line %d] Error at '%v': %s.", token.Line, token.Lexeme, errorMessage)
+	return errors.New(message)
 }
 
 func (p *parser) peek() spec.Token {
 	return (*p.tokens)[p.position]
 }
 
+func (p *parser) peekNext() spec.Token {
+	return p.peekAt(1)
+}
+
+// Peeks at the token the specified number of positions ahead, stopping at EOF.
+func (p *parser) peekAt(offset int) spec.Token {
+	for i := p.position; i < p.position + offset; i++ {
+		if (*p.tokens)[i].Type == spec.EOF {
+			return (*p.tokens)[i]
+		}
+	}
+	return (*p.tokens)[p.position + offset]
+}
+
+// Decides whether a brace at the start of a statement opens a map literal rather than a block, which is the case if
+// it is followed by a simple key and a colon, as in `{"key": value}`. An empty `{}` is a block.
+func (p *parser) isMapLiteralAhead() bool {
+	switch p.peekAt(1).Type {
+	case spec.String, spec.Number, spec.Identifier, spec.True, spec.False, spec.Nil:
+		return p.peekAt(2).Type == spec.Colon
+	}
+	return false
+}
+
+// Checks whether the parenthesis at the current position is followed, after its matching closing parenthesis, by
+// `=>`. Does not consume any tokens.
+func (p *parser) isArrowFunctionAhead() bool {
+	depth := 0
+	for i := p.position; i < len(*p.tokens); i++ {
+		switch (*p.tokens)[i].Type {
+		case spec.LeftParen:
+			depth++
+		case spec.RightParen:
+			depth--
+			if depth == 0 {
+				return i + 1 < len(*p.tokens) && (*p.tokens)[i + 1].Type == spec.Arrow
+			}
+		case spec.EOF:
+			return false
+		}
+	}
+	return false
+}
+
 func (p *parser) previous() spec.Token {
 	return (*p.tokens)[p.position - 1]
 }
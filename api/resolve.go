@@ -1,6 +1,8 @@
 package api
 
 import (
+	"fmt"
+
 	intp "github.com/codecrafters-io/interpreter-starter-go/api/interpreter"
 	"github.com/codecrafters-io/interpreter-starter-go/spec"
 )
@@ -11,6 +13,14 @@ type resolver struct { // implements spec.ExprVisitor[any, error], spec.StmtVisi
 	hadError bool
 	currentFuncType intp.FunctionType
 	currentClassType intp.ClassType
+	isInGenerator bool
+	isInStaticMethod bool
+	traits map[string]spec.TraitStmt // trait declarations by name, to check classes for conflicting trait methods
+	interfaces map[string]spec.InterfaceStmt // interface declarations by name
+	classes map[string]spec.ClassStmt // class declarations by name
+	// Whether to also check that classes implement their interfaces and abstract methods, which is otherwise only
+	// checked when the class is defined at runtime
+	isChecking bool
 }
 
 type stack[T any] struct {
@@ -65,14 +75,17 @@ func (rslv *resolver) resolveExpr(expr spec.Expr) {
 }
 
 func (rslv *resolver) resolveFunction(fs spec.FuncStmt, funcType intp.FunctionType) {
-	origFuncType := rslv.currentFuncType
-	rslv.currentFuncType = funcType
-	defer func() { rslv.currentFuncType = origFuncType }()
+	origFuncType, origIsInGenerator := rslv.currentFuncType, rslv.isInGenerator
+	rslv.currentFuncType, rslv.isInGenerator = funcType, fs.IsGenerator
+	defer func() { rslv.currentFuncType, rslv.isInGenerator = origFuncType, origIsInGenerator }()
 
 	rslv.beginScope()
 	for _, param := range fs.Params {
-		rslv.declare(param);
-    rslv.define(param);
+		if param.Default != nil {
+			rslv.resolveExpr(param.Default)
+		}
+		rslv.declare(param.Name);
+    rslv.define(param.Name);
 	}
 	rslv.resolveStmts(&fs.Body);
 	rslv.endScope()
@@ -168,23 +181,40 @@ func (rslv *resolver) VisitCall(ce spec.CallExpr) (any, error) {
 	for _, arg := range ce.Args {
 		rslv.resolveExpr(arg)
 	}
+	for _, arg := range ce.NamedArgs {
+		rslv.resolveExpr(arg.Value)
+	}
 	return nil, nil
 }
 
 func (rslv *resolver) VisitGet(ge spec.GetExpr) (any, error) {
 	rslv.resolveExpr(ge.Object)
+	rslv.checkPrivateAccess(ge.Object, ge.Name)
 	return nil, nil
 }
 
 func (rslv *resolver) VisitSet(se spec.SetExpr) (any, error) {
 	rslv.resolveExpr(se.Object)
+	rslv.checkPrivateAccess(se.Object, se.Name)
 	rslv.resolveExpr(se.Value)
 	return nil, nil
 }
 
+// Private members can only be accessed on `this`, inside the methods of a class.
+func (rslv *resolver) checkPrivateAccess(object spec.Expr, name spec.Token) {
+	if name.Type != spec.PrivateIdentifier {
+		return
+	}
+	if _, isThis := object.(spec.ThisExpr); !isThis || rslv.currentClassType == intp.CtNone {
+		rslv.reportError(name, "Private member accessed outside class")
+	}
+}
+
 func (rslv *resolver) VisitThis(te spec.ThisExpr) (any, error) {
 	if rslv.currentClassType == intp.CtNone {
 		rslv.reportError(te.Keyword, "Can't use 'this' outside of a class.")
+	} else if rslv.isInStaticMethod {
+		rslv.reportError(te.Keyword, "Can't use 'this' in a static method")
 	}
 	rslv.resolveLocal(te, te.Keyword)
 	return nil, nil
@@ -196,11 +226,103 @@ func (rslv *resolver) VisitSuper(se spec.SuperExpr) (any, error) {
 		rslv.reportError(se.Keyword, "Can't use 'super' outside of a class")
 	case intp.CtClass:
 		rslv.reportError(se.Keyword, "Can't use 'super' in a class with no superclass")
+	case intp.CtTrait:
+		rslv.reportError(se.Keyword, "Can't use 'super' in a trait")
+	default:
+		if rslv.isInStaticMethod {
+			rslv.reportError(se.Keyword, "Can't use 'super' in a static method")
+		}
 	}
 	rslv.resolveLocal(se, se.Keyword)
 	return nil, nil
 }
 
+func (rslv *resolver) VisitOptionalGet(oge spec.OptionalGetExpr) (any, error) {
+	rslv.resolveExpr(oge.Object)
+	return nil, nil
+}
+
+func (rslv *resolver) VisitOptionalChain(oce spec.OptionalChainExpr) (any, error) {
+	rslv.resolveExpr(oce.Expr)
+	return nil, nil
+}
+
+func (rslv *resolver) VisitCoalesce(ce spec.CoalesceExpr) (any, error) {
+	rslv.resolveExpr(ce.Left)
+	rslv.resolveExpr(ce.Right)
+	return nil, nil
+}
+func (rslv *resolver) VisitLambda(le spec.LambdaExpr) (any, error) {
+	rslv.resolveFunction(le.Declaration, intp.FtStandalone)
+	return nil, nil
+}
+func (rslv *resolver) VisitInterpolation(ie spec.InterpolationExpr) (any, error) {
+	for _, part := range ie.Parts {
+		rslv.resolveExpr(part)
+	}
+	return nil, nil
+}
+
+func (rslv *resolver) VisitRange(re spec.RangeExpr) (any, error) {
+	rslv.resolveExpr(re.Start)
+	rslv.resolveExpr(re.End)
+	if re.Step != nil {
+		rslv.resolveExpr(re.Step)
+	}
+	return nil, nil
+}
+
+func (rslv *resolver) VisitList(le spec.ListExpr) (any, error) {
+	for _, element := range le.Elements {
+		rslv.resolveExpr(element)
+	}
+	return nil, nil
+}
+
+func (rslv *resolver) VisitIndexGet(ige spec.IndexGetExpr) (any, error) {
+	rslv.resolveExpr(ige.Object)
+	rslv.resolveExpr(ige.Index)
+	return nil, nil
+}
+
+func (rslv *resolver) VisitIndexSet(ise spec.IndexSetExpr) (any, error) {
+	rslv.resolveExpr(ise.Object)
+	rslv.resolveExpr(ise.Index)
+	rslv.resolveExpr(ise.Value)
+	return nil, nil
+}
+
+func (rslv *resolver) VisitSlice(se spec.SliceExpr) (any, error) {
+	rslv.resolveExpr(se.Object)
+	if se.Start != nil {
+		rslv.resolveExpr(se.Start)
+	}
+	if se.End != nil {
+		rslv.resolveExpr(se.End)
+	}
+	return nil, nil
+}
+func (rslv *resolver) VisitMap(me spec.MapExpr) (any, error) {
+	for i := range me.Keys {
+		rslv.resolveExpr(me.Keys[i])
+		rslv.resolveExpr(me.Values[i])
+	}
+	return nil, nil
+}
+func (rslv *resolver) VisitTuple(te spec.TupleExpr) (any, error) {
+	for _, element := range te.Elements {
+		rslv.resolveExpr(element)
+	}
+	return nil, nil
+}
+
+func (rslv *resolver) VisitTupleAssignment(tae spec.TupleAssignmentExpr) (any, error) {
+	rslv.resolveExpr(tae.Expr)
+	for _, target := range tae.Targets {
+		rslv.resolveLocal(target, target.Identifier)
+	}
+	return nil, nil
+}
 
 // MARK: - StmtVisitor
 
@@ -223,6 +345,17 @@ func (rslv *resolver) VisitDeclare(ds spec.DeclareStmt) error {
 	return nil
 }
 
+func (rslv *resolver) VisitDeclareTuple(dts spec.DeclareTupleStmt) error {
+	for _, identifier := range dts.Identifiers {
+		rslv.declare(identifier)
+	}
+	rslv.resolveExpr(dts.Expr)
+	for _, identifier := range dts.Identifiers {
+		rslv.define(identifier)
+	}
+	return nil
+}
+
 func (rslv *resolver) VisitBlock(bs spec.BlockStmt) error {
 	rslv.beginScope()
 	rslv.resolveStmts(&bs.Statements)
@@ -261,14 +394,38 @@ func (rslv *resolver) VisitReturn(rs spec.ReturnStmt) error {
 			rslv.reportError(rs.Keyword, "Can't return a value from an initializer")
 		}
 		rslv.resolveExpr(rs.Expr)
+		if call, isCall := rs.Expr.(spec.CallExpr); isCall && rslv.currentFuncType != intp.FtInitializer && !rslv.isInGenerator {
+			rslv.intp.ResolveTailCall(call)
+		}
+	}
+	return nil
+}
+
+func (rslv *resolver) VisitYield(ys spec.YieldStmt) error {
+	switch rslv.currentFuncType {
+	case intp.FtNone:
+		rslv.reportError(ys.Keyword, "Can't yield from top-level code")
+	case intp.FtInitializer:
+		rslv.reportError(ys.Keyword, "Can't yield from an initializer")
 	}
+	rslv.resolveExpr(ys.Expr)
+	return nil
+}
+
+func (rslv *resolver) VisitForIn(fis spec.ForInStmt) error {
+	rslv.resolveExpr(fis.Iterable)
+	rslv.beginScope()
+	rslv.declare(fis.Identifier)
+	rslv.define(fis.Identifier)
+	rslv.resolveStmt(fis.Body)
+	rslv.endScope()
 	return nil
 }
 
 func (rslv *resolver) VisitClass(cs spec.ClassStmt) error {
-	origClassType := rslv.currentClassType
-	rslv.currentClassType = intp.CtClass
-	defer func() { rslv.currentClassType = origClassType }()
+	origClassType, origIsInStaticMethod := rslv.currentClassType, rslv.isInStaticMethod
+	rslv.currentClassType, rslv.isInStaticMethod = intp.CtClass, false
+	defer func() { rslv.currentClassType, rslv.isInStaticMethod = origClassType, origIsInStaticMethod }()
 
 	rslv.declare(cs.Name)
 	rslv.define(cs.Name)
@@ -284,8 +441,28 @@ func (rslv *resolver) VisitClass(cs spec.ClassStmt) error {
 		rslv.scopes.peek()["super"] = true
 	}
 
+	rslv.resolveTraits(cs)
+	for _, iface := range cs.Interfaces {
+		rslv.resolveExpr(iface)
+	}
+	if rslv.isChecking {
+		rslv.checkRequiredMethods(cs)
+	}
+	rslv.classes[cs.Name.Lexeme] = cs
+
+	// static methods and field initializers have no instance, so they are resolved outside the scope of `this`
+	rslv.isInStaticMethod = true
+	for _, field := range cs.StaticFields {
+		rslv.resolveExpr(field.Expr)
+	}
+	for _, method := range cs.StaticMethods {
+		rslv.resolveFunction(method, intp.FtMethod)
+	}
+	rslv.isInStaticMethod = false
+
 	rslv.beginScope()
 	rslv.scopes.peek()["this"] = true
+	rslv.resolveFieldInitializers(cs.Fields)
 	for _, method := range cs.Methods {
 		methodType := intp.FtMethod
 		if method.Name.Lexeme == "init" {
@@ -293,6 +470,9 @@ func (rslv *resolver) VisitClass(cs spec.ClassStmt) error {
 		}
 		rslv.resolveFunction(method, intp.FunctionType(methodType))
 	}
+	for _, setter := range cs.Setters {
+		rslv.resolveFunction(setter, intp.FtMethod)
+	}
 
 	if cs.Superclass != nil {
 		rslv.endScope()
@@ -300,3 +480,153 @@ func (rslv *resolver) VisitClass(cs spec.ClassStmt) error {
 	rslv.endScope()
 	return nil
 }
+
+// Resolves the initializers of instance fields like method bodies, in the scope of `this`, and reports fields that are
+// declared more than once.
+func (rslv *resolver) resolveFieldInitializers(fields []spec.DeclareStmt) {
+	origFuncType := rslv.currentFuncType
+	rslv.currentFuncType = intp.FtMethod
+	defer func() { rslv.currentFuncType = origFuncType }()
+	isDeclared := make(map[string]bool)
+	for _, field := range fields {
+		if isDeclared[field.Identifier.Lexeme] {
+			rslv.reportError(field.Identifier, "Already a field with this name in this class.")
+		}
+		isDeclared[field.Identifier.Lexeme] = true
+		rslv.resolveExpr(field.Expr)
+	}
+}
+
+// Resolves the traits a class uses, and reports methods that several of them define but the class doesn't.
+func (rslv *resolver) resolveTraits(cs spec.ClassStmt) {
+	isOwn := make(map[string]bool)
+	for _, method := range cs.Methods {
+		isOwn[method.Name.Lexeme] = true
+	}
+	providers := make(map[string]string)
+	for _, trait := range cs.Traits {
+		rslv.resolveExpr(trait)
+		declaration, isKnown := rslv.traits[trait.Identifier.Lexeme]
+		if !isKnown {
+			continue
+		}
+		for _, method := range declaration.Methods {
+			name := method.Name.Lexeme
+			if provider, contains := providers[name]; contains && provider != declaration.Name.Lexeme && !isOwn[name] {
+				message := fmt.Sprintf("Method '%v' is defined by both %v and %v; %v must override it", name, provider, declaration.Name.Lexeme, cs.Name.Lexeme)
+				rslv.reportError(trait.Identifier, message)
+			}
+			providers[name] = declaration.Name.Lexeme
+		}
+	}
+}
+
+// Reports methods of the class's interfaces that it doesn't have, and methods whose number of parameters doesn't
+// match the interface method or inherited abstract method they implement. Only traits and superclasses that are known
+// declarations are taken into account.
+func (rslv *resolver) checkRequiredMethods(cs spec.ClassStmt) {
+	methods, isComplete := rslv.knownMethods(cs, map[string]bool{cs.Name.Lexeme: true})
+	for _, ifaceExpr := range cs.Interfaces {
+		iface, isKnown := rslv.interfaces[ifaceExpr.Identifier.Lexeme]
+		if !isKnown {
+			continue
+		}
+		for _, required := range iface.Methods {
+			method, found := methods[required.Name.Lexeme]
+			if !found && isComplete {
+				message := fmt.Sprintf(
+					"Class %v doesn't implement method '%v' of interface %v", cs.Name.Lexeme, required.Name.Lexeme, iface.Name.Lexeme,
+				)
+				rslv.reportError(ifaceExpr.Identifier, message)
+			} else if found && len(method.Params) != len(required.Params) {
+				rslv.reportArityMismatch(cs, method, required, "interface " + iface.Name.Lexeme, ifaceExpr.Identifier)
+			}
+		}
+	}
+	if cs.Superclass == nil {
+		return
+	}
+	superclass, isKnown := rslv.classes[cs.Superclass.Identifier.Lexeme]
+	if !isKnown {
+		return
+	}
+	inherited, _ := rslv.knownMethods(superclass, map[string]bool{cs.Name.Lexeme: true, superclass.Name.Lexeme: true})
+	for _, method := range cs.Methods {
+		abstract, found := inherited[method.Name.Lexeme]
+		if found && abstract.IsAbstract && len(method.Params) != len(abstract.Params) {
+			rslv.reportArityMismatch(cs, method, abstract, "abstract method of " + superclass.Name.Lexeme, method.Name)
+		}
+	}
+}
+
+func (rslv *resolver) reportArityMismatch(
+	cs spec.ClassStmt, method spec.FuncStmt, required spec.FuncStmt, requirement string, token spec.Token,
+) {
+	message := fmt.Sprintf(
+		"Method '%v' of class %v takes %v parameters, but %v requires %v",
+		method.Name.Lexeme, cs.Name.Lexeme, len(method.Params), requirement, len(required.Params),
+	)
+	rslv.reportError(token, message)
+}
+
+// Collects the methods of a class, including those it gets from its traits and superclasses, by name. Returns false
+// if a trait or superclass isn't a known declaration, in which case the class may have more methods.
+func (rslv *resolver) knownMethods(cs spec.ClassStmt, visited map[string]bool) (map[string]spec.FuncStmt, bool) {
+	methods, isComplete := make(map[string]spec.FuncStmt), true
+	if cs.Superclass != nil {
+		superclass, isKnown := rslv.classes[cs.Superclass.Identifier.Lexeme]
+		if isKnown && !visited[superclass.Name.Lexeme] {
+			visited[superclass.Name.Lexeme] = true
+			inherited, isSuperclassComplete := rslv.knownMethods(superclass, visited)
+			for name, method := range inherited {
+				methods[name] = method
+			}
+			isComplete = isSuperclassComplete
+		} else {
+			isComplete = false
+		}
+	}
+	for _, traitExpr := range cs.Traits {
+		trait, isKnown := rslv.traits[traitExpr.Identifier.Lexeme]
+		if !isKnown {
+			isComplete = false
+			continue
+		}
+		for _, method := range trait.Methods {
+			methods[method.Name.Lexeme] = method
+		}
+	}
+	for _, method := range cs.Methods {
+		methods[method.Name.Lexeme] = method
+	}
+	return methods, isComplete
+}
+
+func (rslv *resolver) VisitInterface(is spec.InterfaceStmt) error {
+	rslv.declare(is.Name)
+	rslv.define(is.Name)
+	rslv.interfaces[is.Name.Lexeme] = is
+	return nil
+}
+
+func (rslv *resolver) VisitTrait(ts spec.TraitStmt) error {
+	origClassType, origIsInStaticMethod := rslv.currentClassType, rslv.isInStaticMethod
+	rslv.currentClassType, rslv.isInStaticMethod = intp.CtTrait, false
+	defer func() { rslv.currentClassType, rslv.isInStaticMethod = origClassType, origIsInStaticMethod }()
+
+	rslv.declare(ts.Name)
+	rslv.define(ts.Name)
+	rslv.traits[ts.Name.Lexeme] = ts
+
+	rslv.beginScope()
+	rslv.scopes.peek()["this"] = true
+	for _, method := range ts.Methods {
+		methodType := intp.FtMethod
+		if method.Name.Lexeme == "init" {
+			methodType = intp.FtInitializer
+		}
+		rslv.resolveFunction(method, intp.FunctionType(methodType))
+	}
+	rslv.endScope()
+	return nil
+}
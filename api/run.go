@@ -12,6 +12,10 @@ func Eval(expr *spec.Expr) (any, error) {
 	return (*expr).Eval(&intp)
 }
 
+func Stringify(value any) string {
+	return intp.Stringify(value)
+}
+
 func Exec(stmts *[]spec.Stmt) error {
 	intp := intp.NewInterpreter()
 	for _, stmt := range *stmts {
@@ -36,8 +40,29 @@ func ExecWithIntp(intp *intp.Interpreter, stmts *[]spec.Stmt) error {
 }
 
 func ResolveWithIntp(intpr *intp.Interpreter, stmts *[]spec.Stmt) error {
+	return resolve(intpr, stmts, false)
+}
+
+// Resolves the statements without running them, and also checks that classes implement their interfaces and the
+// abstract methods they inherit.
+func Check(stmts *[]spec.Stmt) error {
+	intpr := intp.NewInterpreter()
+	return resolve(&intpr, stmts, true)
+}
+
+func resolve(intpr *intp.Interpreter, stmts *[]spec.Stmt, isChecking bool) error {
 	scopes := stack[map[string]bool]{slice: []map[string]bool{}}
-	rslv := resolver{intp: intpr, scopes: scopes, hadError: false, currentFuncType: intp.FtNone, currentClassType: intp.CtNone}
+	rslv := resolver{
+		intp: intpr,
+		scopes: scopes,
+		hadError: false,
+		currentFuncType: intp.FtNone,
+		currentClassType: intp.CtNone,
+		traits: make(map[string]spec.TraitStmt),
+		interfaces: make(map[string]spec.InterfaceStmt),
+		classes: make(map[string]spec.ClassStmt),
+		isChecking: isChecking,
+	}
 	rslv.resolveStmts(stmts)
 	if rslv.hadError {
 		return errors.New("encountered error(s) in resolver")
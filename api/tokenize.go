@@ -3,8 +3,11 @@ package api
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/codecrafters-io/interpreter-starter-go/spec"
 )
@@ -13,11 +16,41 @@ func Tokenize(input *string) ([]spec.Token, []error) {
 	var line uint64 = 1
 	var tokens []spec.Token
 	var errs []error
+	var interpolations []interpolation
 	runes := []rune(*input)
 	for i := 0; i < len(runes); i++ {
 		char := runes[i]
+		// MARK: Interpolations
+		if top := len(interpolations) - 1; top >= 0 && char == '}' && interpolations[top].depth == 0 {
+			// the interpolation is over, continue the string that contains it
+			current := interpolations[top]
+			interpolations = interpolations[:top]
+			if tokens[len(tokens)-1].Type == spec.Interpolation {
+				message := fmt.Sprintf("[line %v] Error: Expect expression inside interpolation.", line)
+				errs = append(errs, errors.New(message))
+			}
+			index, newlines, isInterpolating, err := scanString(&tokens, &runes, i, i + 1, current.delimiter, false, line)
+			if err != nil {
+				message := fmt.Sprintf("[line %v] Error: %s", line, err.Error())
+				errs = append(errs, errors.New(message))
+			}
+			if isInterpolating {
+				interpolations = append(interpolations, interpolation{delimiter: current.delimiter, line: line + newlines})
+			}
+			i = index
+			line += newlines
+			continue
+		} else if top >= 0 && char == '{' {
+			interpolations[top].depth++
+		} else if top >= 0 && char == '}' {
+			interpolations[top].depth--
+		}
+		// MARK: Multi-character tokens
+		if multiCharTokenType, lexeme, isMultiCharToken := matchMultiCharToken(&runes, i); isMultiCharToken {
+			tokens = append(tokens, spec.Token{Type: multiCharTokenType, Lexeme: lexeme, Literal: nil, Line: line})
+			i += len([]rune(lexeme)) - 1
 		// MARK: Single-character tokens
-		if singleCharTokenType, isSingleCharToken := spec.SingleCharTokens[char]; isSingleCharToken {
+		} else if singleCharTokenType, isSingleCharToken := spec.SingleCharTokens[char]; isSingleCharToken {
 			// handle comments too
 			next, peekError := peek(&runes, i + 1)
 			if peekError == nil && char == '/' && next == '/' {
@@ -36,19 +69,36 @@ func Tokenize(input *string) ([]spec.Token, []error) {
 		} else if char == '<' {
 			i = handleSingleDoubleCharToken(&tokens, &runes, i, line, '=', spec.LessEqual, spec.Less)
 		// MARK: Literals
-		} else if char == '"' {
-			index, err := handleString(&tokens, &runes, i, line)
+		} else if next, _ := peek(&runes, i + 1); char == '"' || (char == 'r' && next == '"') {
+			index, newlines, isInterpolating, err := handleString(&tokens, &runes, i, line)
 			if err != nil {
 				message := fmt.Sprintf("[line %v] Error: %s", line, err.Error())
 				errs = append(errs, errors.New(message))
 			}
+			if isInterpolating {
+				delimiter := `"`
+				if hasRunesAt(&runes, i, `"""`) {
+					delimiter = `"""`
+				}
+				interpolations = append(interpolations, interpolation{delimiter: delimiter, line: line + newlines})
+			}
 			i = index
-		} else if unicode.IsDigit(char) {
-			index := handleNumber(&tokens, &runes, i, line)
+			line += newlines
+		} else if isDecimalDigit(char) {
+			index, err := handleNumber(&tokens, &runes, i, line)
+			if err != nil {
+				message := fmt.Sprintf("[line %v] Error: %s", line, err.Error())
+				errs = append(errs, errors.New(message))
+			}
 			i = index - 1
 		} else if unicode.IsLetter(char) || char == '_' {
 			index := handleIdentifierAndKeyword(&tokens, &runes, i, line)
 			i = index - 1
+		} else if char == '#' && i + 1 < len(runes) && (unicode.IsLetter(runes[i + 1]) || runes[i + 1] == '_') {
+			index := skipUntil(&runes, i + 1, isIdentifierEnd)
+			lexeme := string(runes[i:index])
+			tokens = append(tokens, spec.Token{Type: spec.PrivateIdentifier, Lexeme: lexeme, Literal: nil, Line: line})
+			i = index - 1
 		// MARK: Miscellaneous
 		} else if char == '\n' {
 			line++
@@ -59,6 +109,10 @@ func Tokenize(input *string) ([]spec.Token, []error) {
 			errs = append(errs, errors.New(message))
 		}
 	}
+	for _, unterminated := range interpolations {
+		message := fmt.Sprintf("[line %v] Error: Unterminated interpolation.", unterminated.line)
+		errs = append(errs, errors.New(message))
+	}
 	tokens = append(tokens, spec.Token{Type: spec.EOF, Lexeme: "", Literal: nil, Line: line})
 
 	return tokens, errs
@@ -80,31 +134,250 @@ func handleIdentifierAndKeyword(tokens *[]spec.Token, runes *[]rune, currentPosi
 	return index
 }
 
-// Number handling
-func handleNumber(tokens *[]spec.Token, runes *[]rune, currentPosition int, line uint64) int {
+// Number handling. Follows the Lox grammar (digits, optionally followed by a '.' and at least one more digit), extended
+// with `0x`, `0b` and `0o` prefixes, exponents, and `_` separators between digits. Literals with a fractional part or an
+// exponent are floats, all others are integers.
+func handleNumber(tokens *[]spec.Token, runes *[]rune, currentPosition int, line uint64) (int, error) {
 	slice := *runes
-	index := skipUntil(runes, currentPosition + 1, isNumberEnd)
-	lexeme := string(slice[currentPosition:index])
-	literal, convError := strconv.ParseFloat(lexeme, 64)
-	if convError != nil {
-		panic("could not parse float")
+	var literal any
+	index := currentPosition
+	if base, prefix, isPrefixed := numberPrefix(runes, currentPosition); isPrefixed {
+		end, err := skipDigits(runes, currentPosition + 2, isDigitOfBase(base))
+		if err != nil {
+			return end, err
+		}
+		if end == currentPosition + 2 {
+			return end, fmt.Errorf("Expect digits after '%s'", prefix)
+		}
+		digits := strings.ReplaceAll(string(slice[currentPosition+2:end]), "_", "")
+		integer, _ := new(big.Int).SetString(digits, base)
+		literal, index = normalizeInteger(integer), end
+	} else {
+		end, err := skipDigits(runes, currentPosition, isDecimalDigit)
+		if err != nil {
+			return end, err
+		}
+		isFloat := false
+		if next, peekError := peek(runes, end + 1); peekError == nil && slice[end] == '.' && isDecimalDigit(next) {
+			if end, err = skipDigits(runes, end + 1, isDecimalDigit); err != nil {
+				return end, err
+			}
+			isFloat = true
+		}
+		if end < len(slice) && (slice[end] == 'e' || slice[end] == 'E') {
+			exponentStart := end + 1
+			if exponentStart < len(slice) && (slice[exponentStart] == '+' || slice[exponentStart] == '-') {
+				exponentStart++
+			}
+			if exponentStart >= len(slice) || !isDecimalDigit(slice[exponentStart]) {
+				return skipUntil(runes, exponentStart, isIdentifierEnd), errors.New("Expect digits in exponent")
+			}
+			if end, err = skipDigits(runes, exponentStart, isDecimalDigit); err != nil {
+				return end, err
+			}
+			isFloat = true
+		}
+		text := strings.ReplaceAll(string(slice[currentPosition:end]), "_", "")
+		if isFloat {
+			float, convError := strconv.ParseFloat(text, 64)
+			if convError != nil {
+				return end, fmt.Errorf("Number literal out of range: %s", string(slice[currentPosition:end]))
+			}
+			literal = float
+		} else {
+			integer, _ := new(big.Int).SetString(text, 10)
+			literal = normalizeInteger(integer)
+		}
+		index = end
 	}
+	// a literal that runs straight into letters or digits it can't contain, like `0b12` or `3px`, is malformed
+	if index < len(slice) && !isIdentifierEnd(slice[index]) {
+		end := skipUntil(runes, index, isIdentifierEnd)
+		return end, fmt.Errorf("Invalid number literal: %s", string(slice[currentPosition:end]))
+	}
+	lexeme := string(slice[currentPosition:index])
 	*tokens = append(*tokens, spec.Token{Type: spec.Number, Lexeme: lexeme, Literal: literal, Line: line})
-	return index
+	return index, nil
+}
+
+// Recognizes a `0x`, `0b` or `0o` prefix at the specified position, and returns the base and the prefix.
+func numberPrefix(input *[]rune, position int) (int, string, bool) {
+	next, peekError := peek(input, position + 1)
+	if peekError != nil || (*input)[position] != '0' {
+		return 0, "", false
+	}
+	switch next {
+	case 'x', 'X':
+		return 16, "0" + string(next), true
+	case 'b', 'B':
+		return 2, "0" + string(next), true
+	case 'o', 'O':
+		return 8, "0" + string(next), true
+	}
+	return 0, "", false
+}
+
+// Skips a run of digits that may contain `_` separators, and returns the position after it. A separator is only valid
+// between two digits.
+func skipDigits(input *[]rune, startPosition int, isDigit func(rune) bool) (int, error) {
+	slice, i := *input, startPosition
+	for ; i < len(slice); i++ {
+		if slice[i] == '_' {
+			if i == startPosition || !isDigit(slice[i-1]) || i + 1 >= len(slice) || !isDigit(slice[i+1]) {
+				return skipUntil(input, i, isIdentifierEnd), errors.New("Digit separator '_' must be between digits")
+			}
+		} else if !isDigit(slice[i]) {
+			break
+		}
+	}
+	return i, nil
 }
 
-// String handling
-func handleString(tokens *[]spec.Token, runes *[]rune, currentPosition int, line uint64) (int, error) {
+func isDigitOfBase(base int) func(rune) bool {
+	return func(x rune) bool {
+		switch {
+		case isDecimalDigit(x):
+			return int(x - '0') < base
+		case 'a' <= x && x <= 'f':
+			return int(x - 'a') + 10 < base
+		case 'A' <= x && x <= 'F':
+			return int(x - 'A') + 10 < base
+		}
+		return false
+	}
+}
+
+func normalizeInteger(integer *big.Int) any {
+	if integer.IsInt64() {
+		return integer.Int64()
+	}
+	return integer
+}
+
+// String handling. Supports escape sequences, raw strings prefixed with `r` that keep backslashes as written, and
+// triple-quoted strings that may span several lines. The lexeme is the string as written, and the literal is the
+// decoded text. Returns the position of the last rune consumed, the number of newlines inside the string, and whether
+// the string continues after an interpolation.
+func handleString(tokens *[]spec.Token, runes *[]rune, currentPosition int, line uint64) (int, uint64, bool, error) {
 	slice := *runes
-	index := skipUntil(runes, currentPosition + 1, isStringEnd)
-	if (index >= len(slice) || slice[index] == '\n') {
-		//lint:ignore ST1005 spec requires capitalized message with period at the end
-		return index, errors.New("Unterminated string.")
-	} else {
-		lexeme, literal := string(slice[currentPosition:index+1]), string(slice[currentPosition+1:index])
-		*tokens = append(*tokens, spec.Token{Type: spec.String, Lexeme: lexeme, Literal: literal, Line: line})
+	isRaw := slice[currentPosition] == 'r'
+	quotePosition := currentPosition
+	if isRaw {
+		quotePosition++
 	}
-	return index, nil
+	delimiter := `"`
+	if hasRunesAt(runes, quotePosition, `"""`) {
+		delimiter = `"""`
+	}
+	return scanString(tokens, runes, currentPosition, quotePosition + len(delimiter), delimiter, isRaw, line)
+}
+
+// Scans the contents of a string, starting at the specified position, up to the closing delimiter. If an interpolation
+// `${` comes first (raw strings have none), the text so far becomes an Interpolation token, and the scan resumes once
+// the tokenizer reaches the matching `}`. The final part of an interpolated string is a String token.
+func scanString(
+	tokens *[]spec.Token, runes *[]rune, lexemeStart int, contentStart int, delimiter string, isRaw bool, line uint64,
+) (int, uint64, bool, error) {
+	slice := *runes
+	var literal strings.Builder
+	var newlines uint64
+	var escapeError error
+	i := contentStart
+	for !hasRunesAt(runes, i, delimiter) {
+		if i >= len(slice) {
+			//lint:ignore ST1005 spec requires capitalized message with period at the end
+			return i - 1, newlines, false, errors.New("Unterminated string.")
+		}
+		if !isRaw && hasRunesAt(runes, i, "${") {
+			lexeme := string(slice[lexemeStart:i+2])
+			*tokens = append(*tokens, spec.Token{Type: spec.Interpolation, Lexeme: lexeme, Literal: literal.String(), Line: line})
+			return i + 1, newlines, true, escapeError
+		}
+		if slice[i] == '\\' && !isRaw {
+			decoded, length, err := decodeEscape(runes, i)
+			if err != nil && escapeError == nil {
+				escapeError = err
+			}
+			literal.WriteString(decoded)
+			i += length
+			continue
+		}
+		if slice[i] == '\n' {
+			newlines++
+		}
+		literal.WriteRune(slice[i])
+		i++
+	}
+	end := i + len(delimiter)
+	if escapeError != nil {
+		return end - 1, newlines, false, escapeError
+	}
+	lexeme := string(slice[lexemeStart:end])
+	*tokens = append(*tokens, spec.Token{Type: spec.String, Lexeme: lexeme, Literal: literal.String(), Line: line})
+	return end - 1, newlines, false, nil
+}
+
+// An interpolation inside a string that's still open, awaiting the `}` that continues the string.
+type interpolation struct {
+	// the delimiter of the string that contains the interpolation
+	delimiter string
+	// the number of braces opened inside the interpolation that are yet to be closed
+	depth int
+	line uint64
+}
+
+// Decodes the escape sequence starting with the backslash at the specified position. Returns the decoded text and the
+// number of runes the sequence spans.
+func decodeEscape(input *[]rune, position int) (string, int, error) {
+	next, peekError := peek(input, position + 1)
+	if peekError != nil {
+		return "", 1, nil
+	}
+	switch next {
+	case 'n':
+		return "\n", 2, nil
+	case 't':
+		return "\t", 2, nil
+	case 'r':
+		return "\r", 2, nil
+	case '"':
+		return "\"", 2, nil
+	case '\\':
+		return "\\", 2, nil
+	case '$':
+		return "$", 2, nil
+	case 'u':
+		slice := *input
+		end := position + 2
+		if end < len(slice) && slice[end] == '{' {
+			end = skipUntil(input, end + 1, func(x rune) bool { return !isDigitOfBase(16)(x) })
+			digits := string(slice[position+3:end])
+			if end < len(slice) && slice[end] == '}' && len(digits) >= 1 && len(digits) <= 6 {
+				codePoint, _ := strconv.ParseInt(digits, 16, 32)
+				if utf8.ValidRune(rune(codePoint)) {
+					return string(rune(codePoint)), end - position + 1, nil
+				}
+				return "", end - position + 1, fmt.Errorf("Invalid code point in escape sequence: %s", digits)
+			}
+		}
+		return "", 2, errors.New("Expect '{', 1 to 6 hexadecimal digits and '}' after '\\u'")
+	}
+	if next == '\n' {
+		return "", 1, errors.New("Invalid escape sequence at end of line")
+	}
+	return "", 1, fmt.Errorf("Invalid escape sequence: \\%s", string(next))
+}
+
+// Checks whether the runes at the specified position spell out the specified text.
+func hasRunesAt(input *[]rune, position int, text string) bool {
+	slice := *input
+	for _, char := range text {
+		if position >= len(slice) || slice[position] != char {
+			return false
+		}
+		position++
+	}
+	return true
 }
 
 // Single- and double-character token handling
@@ -124,12 +397,26 @@ func handleSingleDoubleCharToken(
 	return position + len(newToken.Lexeme) - 1
 }
 
+// Multi-character token handling. Tries the longest operator first and returns whether any matched.
+func matchMultiCharToken(input *[]rune, position int) (spec.TokenType, string, bool) {
+	slice := *input
+	for length := 3; length >= 2; length-- {
+		if position + length > len(slice) {
+			continue
+		}
+		lexeme := string(slice[position:position+length])
+		if tokenType, ok := spec.MultiCharTokens[lexeme]; ok {
+			return tokenType, lexeme, true
+		}
+	}
+	return 0, "", false
+}
+
 // MARK: Lookahead functions
 
 var (
 	isNewline       = func(x rune) bool { return x == '\n' }
-	isStringEnd     = func(x rune) bool { return x == '"' }
-	isNumberEnd     = func(x rune) bool { return x != '.' && !unicode.IsDigit(x) }
+	isDecimalDigit  = func(x rune) bool { return '0' <= x && x <= '9' }
 	isIdentifierEnd = func(x rune) bool { return !unicode.IsLetter(x) && !unicode.IsDigit(x) && x != '_' }
 )
 // Looks ahead, starting at the specified position, and until a specified condition is fulfiled or the end of input is
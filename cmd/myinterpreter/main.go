@@ -9,13 +9,22 @@ import (
 
 func main() {
 
-	if len(os.Args) < 3 {
+	args, trace := []string{}, false
+	for _, arg := range os.Args {
+		if arg == "--trace" {
+			trace = true
+		} else {
+			args = append(args, arg)
+		}
+	}
+
+	if len(args) < 3 {
 		fmt.Fprintln(os.Stderr, "Usage: ./your_program.sh tokenize <filename>")
 		os.Exit(1)
 	}
 
-	command := os.Args[1]
-	input := readFile(os.Args[2])
+	command := args[1]
+	input := readFile(args[2])
 
 	switch command {
 	case "tokenize":
@@ -25,26 +34,39 @@ func main() {
 	case "evaluate":
 		evaluateCommand(&input)
 	case "run":
-		runCommand(&input)
+		runCommand(&input, trace)
+	case "check":
+		checkCommand(&input)
 	}
 
 }
 
 // MARK: - Commands
 
-func runCommand(input *string) {
+func runCommand(input *string, trace bool) {
 	tokens, tokenizeErrors := api.Tokenize(input)
 	handleErrors(tokenizeErrors, 65)
 	statements, parseError := api.ParseStmts(&tokens)
 	handleError(parseError, 65)
 	
 	intp := api.NewInterpreter()
+	intp.SetTrace(trace)
 	resolveErr := api.ResolveWithIntp(&intp, &statements)
 	handleErrorSilent(resolveErr, 65)
 	execError := api.ExecWithIntp(&intp, &statements)
 	handleError(execError, 70)
 }
 
+// Reports static errors, including classes that don't implement their interfaces, without running the program.
+func checkCommand(input *string) {
+	tokens, tokenizeErrors := api.Tokenize(input)
+	handleErrors(tokenizeErrors, 65)
+	statements, parseError := api.ParseStmts(&tokens)
+	handleError(parseError, 65)
+	checkError := api.Check(&statements)
+	handleErrorSilent(checkError, 65)
+}
+
 func evaluateCommand(input *string) {
 	tokens, tokenizeErrors := api.Tokenize(input)
 	handleErrors(tokenizeErrors, 65)
@@ -52,11 +74,7 @@ func evaluateCommand(input *string) {
 	handleError(parseError, 65)
 	value, evalError := api.Eval(&expr)
 	handleError(evalError, 70)
-	if value == nil {
-		fmt.Println("nil")
-	} else {
-		fmt.Println(value)
-	}
+	fmt.Println(api.Stringify(value))
 }
 
 func parseCommand(input *string) {
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"math"
-	"reflect"
+	"strings"
 )
 
 // MARK: - Expressions
@@ -29,6 +29,19 @@ type ExprVisitor[R any, E error] interface {
 	VisitSet(setExpr SetExpr) (R, E)
 	VisitThis(thisExpr ThisExpr) (R, E)
 	VisitSuper(superExpr SuperExpr) (R, E)
+	VisitOptionalGet(optionalGetExpr OptionalGetExpr) (R, E)
+	VisitOptionalChain(optionalChainExpr OptionalChainExpr) (R, E)
+	VisitCoalesce(coalesceExpr CoalesceExpr) (R, E)
+	VisitLambda(lambdaExpr LambdaExpr) (R, E)
+	VisitList(listExpr ListExpr) (R, E)
+	VisitIndexGet(indexGetExpr IndexGetExpr) (R, E)
+	VisitIndexSet(indexSetExpr IndexSetExpr) (R, E)
+	VisitSlice(sliceExpr SliceExpr) (R, E)
+	VisitMap(mapExpr MapExpr) (R, E)
+	VisitTuple(tupleExpr TupleExpr) (R, E)
+	VisitTupleAssignment(tupleAssignmentExpr TupleAssignmentExpr) (R, E)
+	VisitInterpolation(interpolationExpr InterpolationExpr) (R, E)
+	VisitRange(rangeExpr RangeExpr) (R, E)
 }
 
 type LiteralExpr struct {
@@ -37,8 +50,8 @@ type LiteralExpr struct {
 func (le LiteralExpr) String() string {
 	if le.Value == nil {
 		return "nil"
-	} else if reflect.TypeOf(le.Value).Kind() == reflect.Float64 {
-		return float64ToString(le.Value.(float64))
+	} else if numberString, isNumber := NumberToString(le.Value); isNumber {
+		return numberString
 	} else {
 		return fmt.Sprint(le.Value)
 	}
@@ -170,8 +183,12 @@ type CallExpr struct {
 	Callee Expr
 	Paren Token
 	Args []Expr
+	NamedArgs []NamedArg
 }
 func (ce CallExpr) String() string {
+	if len(ce.NamedArgs) > 0 {
+		return fmt.Sprintf("%v(%v %v)", ce.Callee, ce.Args, ce.NamedArgs)
+	}
 	return fmt.Sprintf("%v(%v)", ce.Callee, ce.Args)
 }
 func (ce CallExpr) Hash() uint64 {
@@ -181,12 +198,25 @@ func (ce CallExpr) Hash() uint64 {
 	for _, arg := range ce.Args {
 		hash.Write(bytify(arg.Hash()))
 	}
+	for _, arg := range ce.NamedArgs {
+		hash.Write(bytify(arg.Name.Hash()))
+		hash.Write(bytify(arg.Value.Hash()))
+	}
 	return hash.Sum64()
 }
 func (ce CallExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
 	return evaluator.VisitCall(ce)
 }
 
+// An argument passed by name, as in `f(b: 3)`.
+type NamedArg struct {
+	Name Token
+	Value Expr
+}
+func (na NamedArg) String() string {
+	return fmt.Sprintf("%v: %v", na.Name.Lexeme, na.Value)
+}
+
 type GetExpr struct {
 	Object Expr
 	Name Token
@@ -225,6 +255,8 @@ func (se SetExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
 
 type ThisExpr struct {
 	Keyword Token
+	// See VariableExpr.Occurrence.
+	Occurrence float64
 }
 func (te ThisExpr) String() string {
 	return "this"
@@ -232,6 +264,7 @@ func (te ThisExpr) String() string {
 func (te ThisExpr) Hash() uint64 {
 	hash := fnv.New64()
 	hash.Write(bytify(te.Keyword.Hash()))
+	hash.Write(bytifyFloat64(te.Occurrence))
 	hash.Write([]byte("ThisExpr"))
 	return hash.Sum64()
 }
@@ -242,6 +275,8 @@ func (te ThisExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
 type SuperExpr struct {
 	Keyword Token
 	Method Token
+	// See VariableExpr.Occurrence.
+	Occurrence float64
 }
 func (se SuperExpr) String() string {
 	return "super." + se.Method.Lexeme
@@ -250,12 +285,308 @@ func (se SuperExpr) Hash() uint64 {
 	hash := fnv.New64()
 	hash.Write(bytify(se.Keyword.Hash()))
 	hash.Write(bytify(se.Method.Hash()))
+	hash.Write(bytifyFloat64(se.Occurrence))
 	return hash.Sum64()
 }
 func (se SuperExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
 	return evaluator.VisitSuper(se)
 }
 
+type OptionalGetExpr struct {
+	Object Expr
+	Name Token
+}
+func (oge OptionalGetExpr) String() string {
+	return fmt.Sprintf("%v?.%v", oge.Object, oge.Name.Lexeme)
+}
+func (oge OptionalGetExpr) Hash() uint64 {
+	hash := fnv.New64()
+	hash.Write(bytify(oge.Object.Hash()))
+	hash.Write(bytify(oge.Name.Hash()))
+	hash.Write([]byte("OptionalGetExpr"))
+	return hash.Sum64()
+}
+func (oge OptionalGetExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
+	return evaluator.VisitOptionalGet(oge)
+}
+
+// Wraps a chain of calls and property accesses that contains at least one optional get (`?.`). If any optional get
+// in the chain encounters nil, the whole chain evaluates to nil.
+type OptionalChainExpr struct {
+	Expr Expr
+}
+func (oce OptionalChainExpr) String() string {
+	return fmt.Sprint(oce.Expr)
+}
+func (oce OptionalChainExpr) Hash() uint64 {
+	hash := fnv.New64()
+	hash.Write(bytify(oce.Expr.Hash()))
+	hash.Write([]byte("OptionalChainExpr"))
+	return hash.Sum64()
+}
+func (oce OptionalChainExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
+	return evaluator.VisitOptionalChain(oce)
+}
+
+type CoalesceExpr struct {
+	Left Expr
+	Opt Token
+	Right Expr
+}
+func (ce CoalesceExpr) String() string {
+	return fmt.Sprintf("(%v %v %v)", ce.Opt.Lexeme, ce.Left, ce.Right)
+}
+func (ce CoalesceExpr) Hash() uint64 {
+	hash := fnv.New64()
+	hash.Write(bytify(ce.Left.Hash()))
+	hash.Write(bytify(ce.Opt.Hash()))
+	hash.Write(bytify(ce.Right.Hash()))
+	return hash.Sum64()
+}
+func (ce CoalesceExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
+	return evaluator.VisitCoalesce(ce)
+}
+
+// An anonymous function. The declaration's name is the `fun` keyword, or the `=>` of the arrow form.
+type LambdaExpr struct {
+	Declaration FuncStmt
+}
+func (le LambdaExpr) String() string {
+	params := []string{}
+	for _, param := range le.Declaration.Params {
+		params = append(params, param.String())
+	}
+	return fmt.Sprintf("(fun (%v))", strings.Join(params, " "))
+}
+func (le LambdaExpr) Hash() uint64 {
+	hash := fnv.New64()
+	hash.Write(bytify(le.Declaration.Name.Hash()))
+	for _, param := range le.Declaration.Params {
+		hash.Write(bytify(param.Name.Hash()))
+	}
+	hash.Write([]byte("LambdaExpr"))
+	return hash.Sum64()
+}
+func (le LambdaExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
+	return evaluator.VisitLambda(le)
+}
+
+type ListExpr struct {
+	Bracket Token
+	Elements []Expr
+}
+func (le ListExpr) String() string {
+	return fmt.Sprintf("(list %v)", le.Elements)
+}
+func (le ListExpr) Hash() uint64 {
+	hash := fnv.New64()
+	hash.Write(bytify(le.Bracket.Hash()))
+	for _, element := range le.Elements {
+		hash.Write(bytify(element.Hash()))
+	}
+	hash.Write([]byte("ListExpr"))
+	return hash.Sum64()
+}
+func (le ListExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
+	return evaluator.VisitList(le)
+}
+
+type IndexGetExpr struct {
+	Object Expr
+	Bracket Token
+	Index Expr
+}
+func (ige IndexGetExpr) String() string {
+	return fmt.Sprintf("%v[%v]", ige.Object, ige.Index)
+}
+func (ige IndexGetExpr) Hash() uint64 {
+	hash := fnv.New64()
+	hash.Write(bytify(ige.Object.Hash()))
+	hash.Write(bytify(ige.Bracket.Hash()))
+	hash.Write(bytify(ige.Index.Hash()))
+	return hash.Sum64()
+}
+func (ige IndexGetExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
+	return evaluator.VisitIndexGet(ige)
+}
+
+type IndexSetExpr struct {
+	Object Expr
+	Bracket Token
+	Index Expr
+	Value Expr
+}
+func (ise IndexSetExpr) String() string {
+	return fmt.Sprintf("%v[%v] = %v", ise.Object, ise.Index, ise.Value)
+}
+func (ise IndexSetExpr) Hash() uint64 {
+	hash := fnv.New64()
+	hash.Write(bytify(ise.Object.Hash()))
+	hash.Write(bytify(ise.Bracket.Hash()))
+	hash.Write(bytify(ise.Index.Hash()))
+	hash.Write(bytify(ise.Value.Hash()))
+	return hash.Sum64()
+}
+func (ise IndexSetExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
+	return evaluator.VisitIndexSet(ise)
+}
+
+// A slice `object[start:end]`. Start and End are nil when omitted.
+type SliceExpr struct {
+	Object Expr
+	Bracket Token
+	Start Expr
+	End Expr
+}
+func (se SliceExpr) String() string {
+	start, end := "", ""
+	if se.Start != nil {
+		start = fmt.Sprint(se.Start)
+	}
+	if se.End != nil {
+		end = fmt.Sprint(se.End)
+	}
+	return fmt.Sprintf("%v[%v:%v]", se.Object, start, end)
+}
+func (se SliceExpr) Hash() uint64 {
+	hash := fnv.New64()
+	hash.Write(bytify(se.Object.Hash()))
+	hash.Write(bytify(se.Bracket.Hash()))
+	if se.Start != nil {
+		hash.Write(bytify(se.Start.Hash()))
+	}
+	if se.End != nil {
+		hash.Write(bytify(se.End.Hash()))
+	}
+	hash.Write([]byte("SliceExpr"))
+	return hash.Sum64()
+}
+func (se SliceExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
+	return evaluator.VisitSlice(se)
+}
+
+type MapExpr struct {
+	Brace Token
+	Keys []Expr
+	Values []Expr
+}
+func (me MapExpr) String() string {
+	entries := []string{}
+	for i := range me.Keys {
+		entries = append(entries, fmt.Sprintf("%v: %v", me.Keys[i], me.Values[i]))
+	}
+	return fmt.Sprintf("(map %v)", strings.Join(entries, ", "))
+}
+func (me MapExpr) Hash() uint64 {
+	hash := fnv.New64()
+	hash.Write(bytify(me.Brace.Hash()))
+	for i := range me.Keys {
+		hash.Write(bytify(me.Keys[i].Hash()))
+		hash.Write(bytify(me.Values[i].Hash()))
+	}
+	hash.Write([]byte("MapExpr"))
+	return hash.Sum64()
+}
+func (me MapExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
+	return evaluator.VisitMap(me)
+}
+
+type TupleExpr struct {
+	Paren Token
+	Elements []Expr
+}
+func (te TupleExpr) String() string {
+	return fmt.Sprintf("(tuple %v)", te.Elements)
+}
+func (te TupleExpr) Hash() uint64 {
+	hash := fnv.New64()
+	hash.Write(bytify(te.Paren.Hash()))
+	for _, element := range te.Elements {
+		hash.Write(bytify(element.Hash()))
+	}
+	hash.Write([]byte("TupleExpr"))
+	return hash.Sum64()
+}
+func (te TupleExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
+	return evaluator.VisitTuple(te)
+}
+
+// A destructuring assignment `(a, b) = expr`.
+type TupleAssignmentExpr struct {
+	Paren Token
+	Targets []VariableExpr
+	Expr Expr
+}
+func (tae TupleAssignmentExpr) String() string {
+	targets := []string{}
+	for _, target := range tae.Targets {
+		targets = append(targets, target.Identifier.Lexeme)
+	}
+	return fmt.Sprintf("(assign (%v) %v)", strings.Join(targets, " "), tae.Expr)
+}
+func (tae TupleAssignmentExpr) Hash() uint64 {
+	hash := fnv.New64()
+	hash.Write(bytify(tae.Paren.Hash()))
+	for _, target := range tae.Targets {
+		hash.Write(bytify(target.Hash()))
+	}
+	hash.Write(bytify(tae.Expr.Hash()))
+	return hash.Sum64()
+}
+func (tae TupleAssignmentExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
+	return evaluator.VisitTupleAssignment(tae)
+}
+
+type InterpolationExpr struct {
+	Quote Token
+	// String literals and interpolated expressions, in order
+	Parts []Expr
+}
+func (ie InterpolationExpr) String() string {
+	return fmt.Sprintf("(interpolation %v)", ie.Parts)
+}
+func (ie InterpolationExpr) Hash() uint64 {
+	hash := fnv.New64()
+	hash.Write(bytify(ie.Quote.Hash()))
+	for _, part := range ie.Parts {
+		hash.Write(bytify(part.Hash()))
+	}
+	hash.Write([]byte("InterpolationExpr"))
+	return hash.Sum64()
+}
+func (ie InterpolationExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
+	return evaluator.VisitInterpolation(ie)
+}
+
+type RangeExpr struct {
+	Start Expr
+	// `..` for an inclusive range, or `..<` for one that excludes the end
+	Opt Token
+	End Expr
+	// nil if the range has no step
+	Step Expr
+}
+func (re RangeExpr) String() string {
+	if re.Step == nil {
+		return fmt.Sprintf("(%v %v %v)", re.Opt.Lexeme, re.Start, re.End)
+	}
+	return fmt.Sprintf("(%v %v %v step %v)", re.Opt.Lexeme, re.Start, re.End, re.Step)
+}
+func (re RangeExpr) Hash() uint64 {
+	hash := fnv.New64()
+	hash.Write(bytify(re.Start.Hash()))
+	hash.Write(bytify(re.Opt.Hash()))
+	hash.Write(bytify(re.End.Hash()))
+	if re.Step != nil {
+		hash.Write(bytify(re.Step.Hash()))
+	}
+	hash.Write([]byte("RangeExpr"))
+	return hash.Sum64()
+}
+func (re RangeExpr) Eval(evaluator ExprVisitor[any, error]) (any, error) {
+	return evaluator.VisitRange(re)
+}
+
 // MARK: - Helpers
 
 func bytify(hash uint64) []byte {
@@ -16,9 +16,15 @@ type StmtVisitor[R any] interface {
 	VisitWhile(whileStmt WhileStmt) R
 	VisitReturn(returnStmt ReturnStmt) R
 	VisitClass(classStmt ClassStmt) R
+	VisitTrait(traitStmt TraitStmt) R
+	VisitInterface(interfaceStmt InterfaceStmt) R
+	VisitYield(yieldStmt YieldStmt) R
+	VisitForIn(forInStmt ForInStmt) R
+	VisitDeclareTuple(declareTupleStmt DeclareTupleStmt) R
 }
 
 type PrintStmt struct {
+	Keyword Token
 	Expr Expr
 }
 func (ps PrintStmt) Exec(executor StmtVisitor[error]) error {
@@ -43,6 +49,16 @@ func (ds DeclareStmt) Exec(executor StmtVisitor[error]) error {
 	return executor.VisitDeclare(ds)
 }
 
+// A destructuring declaration `var (a, b) = expr;`.
+type DeclareTupleStmt struct {
+	Paren Token
+	Identifiers []Token
+	Expr Expr
+}
+func (dts DeclareTupleStmt) Exec(executor StmtVisitor[error]) error {
+	return executor.VisitDeclareTuple(dts)
+}
+
 type BlockStmt struct {
 	Statements []Stmt
 }
@@ -69,13 +85,35 @@ func (ws WhileStmt) Exec(executor StmtVisitor[error]) error {
 
 type FuncStmt struct {
 	Name Token
-	Params []Token
+	Params []Param
 	Body []Stmt
+	// Whether the body contains a `yield` statement (not counting nested functions).
+	IsGenerator bool
+	// Whether this is a getter, declared in a class body without a parameter list.
+	IsGetter bool
+	// Whether this is an abstract method, declared in a class body without a body, which subclasses must implement.
+	IsAbstract bool
 }
 func (fs FuncStmt) Exec(executor StmtVisitor[error]) error {
 	return executor.VisitFunc(fs)
 }
 
+type Param struct {
+	Name Token
+	// The default value of an optional parameter; nil if the parameter is required.
+	Default Expr
+	// Whether this is a rest parameter (`...name`), which collects all remaining positional arguments into a list.
+	IsRest bool
+}
+func (p Param) String() string {
+	if p.IsRest {
+		return "..." + p.Name.Lexeme
+	} else if p.Default != nil {
+		return fmt.Sprintf("%v = %v", p.Name.Lexeme, p.Default)
+	}
+	return p.Name.Lexeme
+}
+
 type ReturnStmt struct {
 	Keyword Token
 	Expr Expr
@@ -88,7 +126,53 @@ type ClassStmt struct {
 	Name Token
 	Methods []FuncStmt
 	Superclass *VariableExpr
+	// Fields declared with `var`, which are initialized for each new instance before `init` runs
+	Fields []DeclareStmt
+	// Methods and fields declared with `class`, which belong to the class itself
+	StaticMethods []FuncStmt
+	StaticFields []DeclareStmt
+	// Methods declared with `set`, which are called when the property is assigned to
+	Setters []FuncStmt
+	// The traits listed after `with`, whose methods are copied into the class
+	Traits []VariableExpr
+	// The interfaces listed after `implements`, whose methods the class must have
+	Interfaces []VariableExpr
 }
 func (cs ClassStmt) Exec(executor StmtVisitor[error]) error {
 	return executor.VisitClass(cs)
 }
+
+type TraitStmt struct {
+	Name Token
+	Methods []FuncStmt
+}
+func (ts TraitStmt) Exec(executor StmtVisitor[error]) error {
+	return executor.VisitTrait(ts)
+}
+
+type InterfaceStmt struct {
+	Name Token
+	// The required methods, which have no body
+	Methods []FuncStmt
+}
+func (is InterfaceStmt) Exec(executor StmtVisitor[error]) error {
+	return executor.VisitInterface(is)
+}
+
+type YieldStmt struct {
+	Keyword Token
+	Expr Expr
+}
+func (ys YieldStmt) Exec(executor StmtVisitor[error]) error {
+	return executor.VisitYield(ys)
+}
+
+type ForInStmt struct {
+	Identifier Token
+	Keyword Token
+	Iterable Expr
+	Body Stmt
+}
+func (fis ForInStmt) Exec(executor StmtVisitor[error]) error {
+	return executor.VisitForIn(fis)
+}
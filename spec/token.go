@@ -3,7 +3,10 @@ package spec
 import (
 	"fmt"
 	"hash/fnv"
-	"reflect"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
 )
 
 // MARK: - Token types
@@ -15,6 +18,9 @@ const (
 	RightParen
 	LeftBrace
 	RightBrace
+	LeftBracket
+	RightBracket
+	Colon
 	Comma
 	Dot
 	Minus
@@ -22,6 +28,11 @@ const (
 	Semicolon
 	Slash
 	Star
+	Percent
+	Ampersand
+	Pipe
+	Caret
+	Tilde
 	// Single- or double-character tokens
 	Bang
 	BangEqual
@@ -31,9 +42,24 @@ const (
 	GreaterEqual
 	Less
 	LessEqual
+	// Multi-character tokens
+	QuestionDot
+	QuestionQuestion
+	Arrow
+	DotDotDot
+	StarStar
+	TildeSlash
+	LessLess
+	GreaterGreater
+	DotDot
+	DotDotLess
 	// Literals
 	Identifier
+	// An identifier prefixed with '#', which names a private member of a class
+	PrivateIdentifier
 	String
+	// The part of an interpolated string that comes before an interpolation
+	Interpolation
 	Number
 	// Keywords
 	And
@@ -43,15 +69,22 @@ const (
 	Fun
 	For
 	If
+	Implements
+	In
+	Interface
+	Is
 	Nil
 	Or
 	Print
 	Return
 	Super
 	This
+	Trait
 	True
 	Var
 	While
+	With
+	Yield
 	// No-character tokens
 	EOF
 )
@@ -66,6 +99,12 @@ func (tt TokenType) String() string {
 		return "LEFT_BRACE"
 	case RightBrace:
 		return "RIGHT_BRACE"
+	case LeftBracket:
+		return "LEFT_BRACKET"
+	case RightBracket:
+		return "RIGHT_BRACKET"
+	case Colon:
+		return "COLON"
 	case Comma:
 		return "COMMA"
 	case Dot:
@@ -80,6 +119,16 @@ func (tt TokenType) String() string {
 		return "SLASH"
 	case Star:
 		return "STAR"
+	case Percent:
+		return "PERCENT"
+	case Ampersand:
+		return "AMPERSAND"
+	case Pipe:
+		return "PIPE"
+	case Caret:
+		return "CARET"
+	case Tilde:
+		return "TILDE"
 	case Bang:
 		return "BANG"
 	case BangEqual:
@@ -96,10 +145,34 @@ func (tt TokenType) String() string {
 		return "LESS"
 	case LessEqual:
 		return "LESS_EQUAL"
+	case QuestionDot:
+		return "QUESTION_DOT"
+	case QuestionQuestion:
+		return "QUESTION_QUESTION"
+	case Arrow:
+		return "ARROW"
+	case DotDotDot:
+		return "DOT_DOT_DOT"
+	case StarStar:
+		return "STAR_STAR"
+	case TildeSlash:
+		return "TILDE_SLASH"
+	case LessLess:
+		return "LESS_LESS"
+	case GreaterGreater:
+		return "GREATER_GREATER"
+	case DotDot:
+		return "DOT_DOT"
+	case DotDotLess:
+		return "DOT_DOT_LESS"
 	case Identifier:
 		return "IDENTIFIER"
+	case PrivateIdentifier:
+		return "PRIVATE_IDENTIFIER"
 	case String:
 		return "STRING"
+	case Interpolation:
+		return "INTERPOLATION"
 	case Number:
 		return "NUMBER"
 	case And:
@@ -116,6 +189,14 @@ func (tt TokenType) String() string {
 		return "FOR"
 	case If:
 		return "IF"
+	case Implements:
+		return "IMPLEMENTS"
+	case In:
+		return "IN"
+	case Interface:
+		return "INTERFACE"
+	case Is:
+		return "IS"
 	case Nil:
 		return "NIL"
 	case Or:
@@ -128,12 +209,18 @@ func (tt TokenType) String() string {
 		return "SUPER"
 	case This:
 		return "THIS"
+	case Trait:
+		return "TRAIT"
 	case True:
 		return "TRUE"
 	case Var:
 		return "VAR"
 	case While:
 		return "WHILE"
+	case With:
+		return "WITH"
+	case Yield:
+		return "YIELD"
 	case EOF:
 		return "EOF"
 	}
@@ -148,15 +235,22 @@ var Keywords = map[string]TokenType {
 	"fun": Fun,
 	"for": For,
 	"if": If,
+	"implements": Implements,
+	"in": In,
+	"interface": Interface,
+	"is": Is,
 	"nil": Nil,
 	"or": Or,
 	"print": Print,
 	"return": Return,
 	"super": Super,
 	"this": This,
+	"trait": Trait,
 	"true": True,
 	"var": Var,
 	"while": While,
+	"with": With,
+	"yield": Yield,
 }
 
 var SingleCharTokens = map[rune]TokenType {
@@ -164,6 +258,9 @@ var SingleCharTokens = map[rune]TokenType {
 	')': RightParen,
 	'{': LeftBrace,
 	'}': RightBrace,
+	'[': LeftBracket,
+	']': RightBracket,
+	':': Colon,
 	',': Comma,
 	'.': Dot,
 	'-': Minus,
@@ -171,6 +268,27 @@ var SingleCharTokens = map[rune]TokenType {
 	'/': Slash,
 	';': Semicolon,
 	'*': Star,
+	'%': Percent,
+	'&': Ampersand,
+	'|': Pipe,
+	'^': Caret,
+	'~': Tilde,
+}
+
+// Operators made up of two or three characters, which the tokenizer tries, longest first, before single-character
+// tokens.
+var MultiCharTokens = map[string]TokenType {
+	"?.": QuestionDot,
+	"??": QuestionQuestion,
+	"=>": Arrow,
+	"...": DotDotDot,
+	"**": StarStar,
+	// floor division; spelled `~/` because `//` already starts a comment
+	"~/": TildeSlash,
+	"<<": LessLess,
+	">>": GreaterGreater,
+	"..": DotDot,
+	"..<": DotDotLess,
 }
 
 // MARK: - Token
@@ -185,8 +303,8 @@ func (token Token) String() string {
 	literalString := ""
 	if (token.Literal == nil) {
 		literalString = "null"
-	} else if reflect.TypeOf(token.Literal).Kind() == reflect.Float64 {
-		literalString = float64ToString(token.Literal.(float64))
+	} else if numberString, isNumber := NumberToString(token.Literal); isNumber {
+		literalString = numberString
 	} else {
 		literalString = fmt.Sprintf("%v", token.Literal)
 	}
@@ -199,10 +317,33 @@ func (token Token) Hash() uint64 {
 	return hash.Sum64()
 }
 
-func float64ToString(number float64) string {
-	literalString := fmt.Sprintf("%g", number)
-	if number == float64(int(number)) {
+// Formats a number as a literal, the way tokens and parsed expressions show it. Numbers are int64 or *big.Int for
+// integers and float64 otherwise; either kind always shows a fractional part (`42.0`). Returns false if the value is
+// not a number.
+func NumberToString(number any) (string, bool) {
+	literalString, isNumber := FormatNumber(number)
+	if float, isFloat := number.(float64); isFloat && (math.IsInf(float, 0) || math.IsNaN(float)) {
+		return literalString, true
+	} else if isNumber && !strings.ContainsAny(literalString, ".e") {
 		literalString = literalString + ".0"
 	}
-	return literalString
+	return literalString, isNumber
+}
+
+// Formats a number the way `print` shows it: integers, and floats with no fractional part that are within the range
+// of an int64, in full without a decimal point; all other floats in the shortest form, with an exponent if it's large
+// (`1e-06`). Returns false if the value is not a number.
+func FormatNumber(number any) (string, bool) {
+	switch number := number.(type) {
+	case int64:
+		return strconv.FormatInt(number, 10), true
+	case *big.Int:
+		return number.String(), true
+	case float64:
+		if number == math.Trunc(number) && math.Abs(number) < math.MaxInt64 {
+			return strconv.FormatFloat(number, 'f', 0, 64), true
+		}
+		return strconv.FormatFloat(number, 'g', -1, 64), true
+	}
+	return "", false
 }